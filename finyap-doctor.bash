@@ -0,0 +1,157 @@
+#!/bin/bash
+
+# finyap-doctor: Scenario file validator
+# Checks TSV scenario files for the problems that make finyap.bash silently
+# skip or mangle sentences, and reports each one with its file and line.
+
+FINYAP_VERSION="0.0.2"
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options] [FILE|DIR ...]
+
+Validate finyap scenario files.
+
+Checks every TSV file given (directories are searched for *.tsv files) and
+reports, with line numbers:
+  - lines with fewer than 2 or more than 4 tab-separated columns
+  - empty Finnish sentences or empty English translations
+  - leading, trailing or doubled whitespace inside a column
+  - bytes that are not valid UTF-8
+  - Finnish sentences that appear in more than one file
+
+Blank lines, '#' comment lines, a UTF-8 byte order mark and Windows (CRLF)
+line endings are all accepted, as finyap.bash handles them. So is repeating a
+Finnish sentence within one file to give it several English translations.
+Fields quoted by a spreadsheet export ("like this") are unwrapped before they
+are checked, just as finyap.bash unwraps them.
+
+With no arguments, checks the directories in \$FINYAP_SCENARIO_DIRS
+(colon-separated), or scenarios/ if that is unset.
+
+Options:
+  -h, --help      Show this help message and exit.
+      --version   Show script version and exit.
+EOF
+}
+
+# --- Argument Parsing ---
+targets=()
+while [[ $# -gt 0 ]]; do
+  key="$1"
+  case $key in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  *)
+    targets+=("$1")
+    shift
+    ;;
+  esac
+done
+
+if [[ ${#targets[@]} -eq 0 ]]; then
+  IFS=':' read -r -a targets <<<"${FINYAP_SCENARIO_DIRS:-scenarios/}"
+fi
+
+# --- ANSI Colors ---
+C_RESET=$'\033[0m'
+C_GREEN=$'\033[1;32m'
+C_YELLOW=$'\033[1;33m'
+C_RED=$'\033[1;31m'
+
+# --- Collect the files to check ---
+tsv_files=()
+for target in "${targets[@]}"; do
+  if [[ -d "$target" ]]; then
+    while IFS= read -r file; do
+      tsv_files+=("$file")
+    done < <(find "$target" -name "*.tsv" -type f | sort)
+  elif [[ -f "$target" ]]; then
+    tsv_files+=("$target")
+  else
+    echo "Error: '$target' is not a file or directory." >&2
+    exit 1
+  fi
+done
+
+if [[ ${#tsv_files[@]} -eq 0 ]]; then
+  echo "Error: No .tsv files found in: ${targets[*]}" >&2
+  exit 1
+fi
+
+# --- Scenario file reading (unwrap_tsv, to_nfc) ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
+# --- Non-UTF-8 bytes ---
+# grep in a UTF-8 locale refuses to match '.*' against a line with invalid bytes.
+utf8_problems=$(LC_ALL=C.UTF-8 grep -naxv '.*' "${tsv_files[@]}" /dev/null |
+  awk -F: '{ printf "%s:%s: line is not valid UTF-8\n", $1, $2 }')
+
+# --- Structural problems and duplicates, in a single pass over every file ---
+# Each file is read the way normalize_tsv reads it for finyap.bash, so
+# quoted fields are unwrapped before the columns are counted and compared.
+# Lines arrive as "file index <TAB> line number <TAB> columns...".
+line_problems=$(
+  for i in "${!tsv_files[@]}"; do
+    unwrap_tsv --numbered <"${tsv_files[i]}" | sed "s/^/$i\t/"
+  done | to_nfc | TSV_FILES="$(printf '%s\n' "${tsv_files[@]}")" LC_ALL=C awk -F'\t' '
+  BEGIN {
+    split(ENVIRON["TSV_FILES"], names, "\n")
+  }
+  {
+    file = names[$1 + 1]
+    where = file ":" $2 ": "
+    columns = NF - 2
+    if (columns < 2) {
+      print where "expected at least 2 tab-separated columns, found " columns
+    } else if (columns > 4) {
+      print where "expected at most 4 tab-separated columns, found " columns
+    }
+    if ($3 ~ /^[ \t]*$/) {
+      print where "empty Finnish sentence"
+    }
+    if (columns >= 2 && $4 ~ /^[ ]*$/) {
+      print where "empty English translation"
+    }
+    for (i = 3; i <= NF; i++) {
+      if ($i ~ /^ | $/) {
+        print where "column " (i - 2) " has leading or trailing whitespace"
+      } else if ($i ~ /  /) {
+        print where "column " (i - 2) " has doubled spaces"
+      }
+    }
+    if ($3 != "") {
+      key = $3
+      gsub(/^ +| +$/, "", key)
+      if (!(key in first_seen)) {
+        first_seen[key] = file ":" $2
+        first_file[key] = file
+      } else if (first_file[key] != file) {
+        print where "duplicate Finnish sentence, first seen at " first_seen[key]
+      }
+    }
+  }
+'
+)
+
+problems=$(printf '%s\n%s\n' "$utf8_problems" "$line_problems" | grep -v '^$' | sort -t: -k1,1 -k2,2n)
+
+if [[ -z "$problems" ]]; then
+  echo -e "${C_GREEN}Checked ${#tsv_files[@]} file(s). No problems found.${C_RESET}"
+  exit 0
+fi
+
+echo "$problems" | while IFS= read -r problem; do
+  echo -e "${C_YELLOW}${problem%%: *}${C_RESET}: ${problem#*: }"
+done
+problem_count=$(echo "$problems" | wc -l | xargs)
+echo ""
+echo -e "${C_RED}Checked ${#tsv_files[@]} file(s). Found ${problem_count} problem(s).${C_RESET}"
+exit 1
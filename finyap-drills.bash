@@ -0,0 +1,189 @@
+#!/bin/bash
+
+# finyap-drills: Grammar drill scenario generator
+# Writes a TSV scenario built from sentence templates (weekdays, clock times,
+# prices, ordinals, dates), with every inflected form spelled out correctly.
+
+FINYAP_VERSION="0.0.2"
+DRILL="" # Which drill to generate, see --drill
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options] --drill DRILL
+
+Generate a finyap scenario that drills one mechanical skill.
+
+Drills:
+  weekdays   Tänään on maanantai. / Nähdään maanantaina.
+  times      Kello on puoli neljä. / Kello on varttia vaille viisi.
+  prices     Tämä maksaa yhden euron. / Tämä maksaa kaksikymmentä euroa.
+  ordinals   Asun viidennessä kerroksessa.
+  dates      Syntymäpäiväni on viides toukokuuta.
+
+Every combination the template allows is written out, and finyap picks from
+them at random. Write the scenario to a file, or play it without one:
+
+  bash $(basename "$0") --drill times > scenarios/drill-times.tsv
+  bash finyap.bash --input <(bash $(basename "$0") --drill dates)
+
+For plain numbers, see finyap-numbers.bash.
+
+Options:
+  -h, --help      Show this help message and exit.
+      --version   Show script version and exit.
+  --drill DRILL   One of: weekdays, times, prices, ordinals, dates.
+EOF
+}
+
+# --- Argument Parsing ---
+while [[ $# -gt 0 ]]; do
+  key="$1"
+  case $key in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  --drill)
+    case "$2" in
+    weekdays | times | prices | ordinals | dates)
+      DRILL="$2"
+      shift 2
+      ;;
+    *)
+      echo "Error: --drill must be one of: weekdays, times, prices, ordinals, dates." >&2
+      exit 1
+      ;;
+    esac
+    ;;
+  *)
+    echo "Error: Unknown option '$1'." >&2
+    exit 1
+    ;;
+  esac
+done
+
+if [[ -z "$DRILL" ]]; then
+  show_help >&2
+  exit 1
+fi
+
+# --- Word lists ---
+ONES=("" "yksi" "kaksi" "kolme" "neljä" "viisi" "kuusi" "seitsemän" "kahdeksan" "yhdeksän")
+# Ordinals 1-10, nominative and inessive (-ssa/-ssä), and the forms used inside 11-19.
+ORDINALS=("" "ensimmäinen" "toinen" "kolmas" "neljäs" "viides" "kuudes" "seitsemäs" "kahdeksas" "yhdeksäs" "kymmenes")
+ORDINALS_INESSIVE=("" "ensimmäisessä" "toisessa" "kolmannessa" "neljännessä" "viidennessä" "kuudennessa" "seitsemännessä" "kahdeksannessa" "yhdeksännessä" "kymmenennessä")
+TEEN_ORDINALS=("" "yhdes" "kahdes" "kolmas" "neljäs" "viides" "kuudes" "seitsemäs" "kahdeksas" "yhdeksäs")
+ENGLISH_ORDINALS=("" "first" "second" "third" "fourth" "fifth" "sixth" "seventh" "eighth" "ninth" "tenth")
+WEEKDAYS=("maanantai" "tiistai" "keskiviikko" "torstai" "perjantai" "lauantai" "sunnuntai")
+ENGLISH_WEEKDAYS=("Monday" "Tuesday" "Wednesday" "Thursday" "Friday" "Saturday" "Sunday")
+# Month names in the partitive, as used in dates (viides toukokuuta), and their lengths.
+MONTHS=("tammikuuta" "helmikuuta" "maaliskuuta" "huhtikuuta" "toukokuuta" "kesäkuuta"
+  "heinäkuuta" "elokuuta" "syyskuuta" "lokakuuta" "marraskuuta" "joulukuuta")
+ENGLISH_MONTHS=("January" "February" "March" "April" "May" "June"
+  "July" "August" "September" "October" "November" "December")
+MONTH_DAYS=(31 29 31 30 31 30 31 31 30 31 30 31)
+
+# Spells out 1-100 in the nominative.
+spell_number() {
+  local n="$1"
+  if [[ $n -eq 100 ]]; then
+    echo "sata"
+  elif [[ $n -ge 20 ]]; then
+    echo "${ONES[n / 10]}kymmentä${ONES[n % 10]}"
+  elif [[ $n -eq 10 ]]; then
+    echo "kymmenen"
+  elif [[ $n -gt 10 ]]; then
+    echo "${ONES[n - 10]}toista"
+  else
+    echo "${ONES[n]}"
+  fi
+}
+
+# Spells out the ordinals 1-31 in the nominative, as used for days of the month.
+spell_ordinal() {
+  local n="$1"
+  local tens=$((n / 10))
+  local units=$((n % 10))
+  if [[ $n -le 10 ]]; then
+    echo "${ORDINALS[n]}"
+  elif [[ $n -lt 20 ]]; then
+    echo "${TEEN_ORDINALS[units]}toista"
+  else
+    # kahdeskymmenes, kahdeskymmenesensimmäinen, kolmaskymmenes...
+    echo "${TEEN_ORDINALS[tens]}kymmenes${ORDINALS[units]}"
+  fi
+}
+
+# 1st, 2nd, 3rd, 4th... 11th, 12th, 13th... 21st.
+english_ordinal_suffix() {
+  local n="$1"
+  if [[ $((n % 100)) -ge 11 && $((n % 100)) -le 13 ]]; then
+    echo "${n}th"
+  else
+    case $((n % 10)) in
+    1) echo "${n}st" ;;
+    2) echo "${n}nd" ;;
+    3) echo "${n}rd" ;;
+    *) echo "${n}th" ;;
+    esac
+  fi
+}
+
+# --- Drills ---
+drill_weekdays() {
+  local i day
+  for i in "${!WEEKDAYS[@]}"; do
+    day="${WEEKDAYS[i]}"
+    printf 'Tänään on %s.\tToday is %s.\t%s = %s\n' "$day" "${ENGLISH_WEEKDAYS[i]}" "$day" "${ENGLISH_WEEKDAYS[i]}"
+    # The essive (-na/-nä) says on which day: maanantaina, keskiviikkona.
+    printf 'Nähdään %sna.\tSee you on %s.\t%s+na = on %s (essive)\n' "$day" "${ENGLISH_WEEKDAYS[i]}" "$day" "${ENGLISH_WEEKDAYS[i]}"
+  done
+}
+
+drill_times() {
+  local hour next
+  for ((hour = 1; hour <= 12; hour++)); do
+    next=$((hour % 12 + 1))
+    printf 'Kello on %s.\tIt is %d:00.\n' "$(spell_number "$hour")" "$hour"
+    printf 'Kello on varttia yli %s.\tIt is %d:15.\tvarttia yli = a quarter past\n' "$(spell_number "$hour")" "$hour"
+    # Half hours count towards the next hour: 3:30 is "half four".
+    printf 'Kello on puoli %s.\tIt is %d:30.\tpuoli %s = half (of the way to) %d\n' "$(spell_number "$next")" "$hour" "$(spell_number "$next")" "$next"
+    printf 'Kello on varttia vaille %s.\tIt is %d:45.\tvarttia vaille = a quarter to\n' "$(spell_number "$next")" "$hour"
+  done
+}
+
+drill_prices() {
+  local euros
+  # One euro is a countable object (yhden euron); after other numbers comes the partitive (euroa).
+  printf 'Tämä maksaa yhden euron.\tThis costs 1 euro.\tyhden euron = one euro (accusative)\n'
+  for ((euros = 2; euros <= 100; euros++)); do
+    printf 'Tämä maksaa %s euroa.\tThis costs %d euros.\teuro+a = partitive after a number\n' "$(spell_number "$euros")" "$euros"
+  done
+}
+
+drill_ordinals() {
+  local floor
+  for ((floor = 1; floor <= 10; floor++)); do
+    printf 'Asun %s kerroksessa.\tI live on the %s floor.\t%s -> %s (inessive, agrees with kerroksessa)\n' \
+      "${ORDINALS_INESSIVE[floor]}" "${ENGLISH_ORDINALS[floor]}" "${ORDINALS[floor]}" "${ORDINALS_INESSIVE[floor]}"
+  done
+}
+
+drill_dates() {
+  local month day
+  for month in "${!MONTHS[@]}"; do
+    for ((day = 1; day <= MONTH_DAYS[month]; day++)); do
+      printf 'Syntymäpäiväni on %s %s.\tMy birthday is on the %s of %s.\t%s = the %s; %s = of %s (partitive)\n' \
+        "$(spell_ordinal "$day")" "${MONTHS[month]}" "$(english_ordinal_suffix "$day")" "${ENGLISH_MONTHS[month]}" \
+        "$(spell_ordinal "$day")" "$(english_ordinal_suffix "$day")" "${MONTHS[month]}" "${ENGLISH_MONTHS[month]}"
+    done
+  done
+}
+
+echo "# Finnish ${DRILL} drill, generated by $(basename "$0") --drill ${DRILL}"
+"drill_${DRILL}"
@@ -0,0 +1,289 @@
+#!/bin/bash
+
+# finyap-lib.bash: Helpers shared by the finyap scripts
+# The scripts source this file, so that they all read scenario files, shuffle
+# and mark or explain words exactly the same way. Not meant to be run.
+
+# --- Helper function to compose accented letters (Unicode NFC) ---
+# Text copied from some sources spells ä as a + a combining diaeresis, which
+# looks identical but never matches the ä you type, nor the cipher's [äöy].
+# Uses python3 or uconv if either is installed, and passes text through otherwise.
+# Bytes that aren't valid UTF-8 must not cost the whole file: python3 keeps them
+# as they are, and uconv replaces them with U+FFFD. finyap-doctor reports them.
+to_nfc() {
+  if command -v python3 &>/dev/null; then
+    python3 -c 'import sys, unicodedata; sys.stdout.buffer.write(unicodedata.normalize("NFC", sys.stdin.buffer.read().decode("utf-8", "surrogateescape")).encode("utf-8", "surrogateescape"))'
+  elif command -v uconv &>/dev/null; then
+    uconv --from-callback substitute -x any-nfc
+  else
+    cat
+  fi
+}
+
+# --- Helper function to normalize a TSV file read on stdin ---
+# Makes spreadsheet exports safe for the scripts' cut/shuf pipelines:
+# 1. Strips a leading UTF-8 byte order mark and Windows (CRLF) line endings.
+# 2. Drops blank lines and lines starting with '#' (comments).
+# 3. Unwraps fields quoted as a whole ("like ""this"""), turning doubled
+#    quotes back into single ones and literal tabs inside them into spaces.
+#    A field that merely starts with a quote, like "Hei", hän sanoi., is kept as is.
+# 4. Composes decomposed accented letters, see to_nfc.
+normalize_tsv() {
+  unwrap_tsv | to_nfc
+}
+
+# --- Helper function doing steps 1-3 of normalize_tsv ---
+# With --numbered, each line is prefixed with its line number in the original
+# file and a tab, so that finyap-doctor can report where a problem is.
+unwrap_tsv() {
+  local numbered=0
+  if [[ "$1" == "--numbered" ]]; then
+    numbered=1
+  fi
+  LC_ALL=C awk -v numbered="$numbered" '
+    NR == 1 { sub(/^\357\273\277/, "") }
+    { sub(/\r$/, "") }
+    /^[ \t]*(#|$)/ { next }
+    numbered { printf "%d\t", FNR }
+    index($0, "\"") == 0 { print; next }
+    {
+      n = length($0)
+      i = 1
+      out = ""
+      first = 1
+      while (1) {
+        quoted = 0
+        if (substr($0, i, 1) == "\"") {
+          # Look for the closing quote, skipping doubled ones.
+          j = i + 1
+          while (j <= n) {
+            if (substr($0, j, 1) == "\"") {
+              if (substr($0, j + 1, 1) != "\"") break
+              j++
+            }
+            j++
+          }
+          if (j <= n && (j == n || substr($0, j + 1, 1) == "\t")) {
+            field = substr($0, i + 1, j - i - 1)
+            gsub(/""/, "\"", field)
+            gsub(/\t/, " ", field)
+            i = j + 1
+            quoted = 1
+          }
+        }
+        if (!quoted) {
+          t = index(substr($0, i), "\t")
+          if (t == 0) {
+            field = substr($0, i)
+            i = n + 1
+          } else {
+            field = substr($0, i, t - 1)
+            i += t - 1
+          }
+        }
+        out = first ? field : out "\t" field
+        first = 0
+        if (i > n) break
+        i++ # past the tab
+      }
+      print out
+    }
+  '
+}
+
+# --- Helper function to merge repeated Finnish sentences ---
+# Rows sharing the same Finnish sentence become one row whose English column
+# lists every distinct translation, separated by " / ". Any further columns
+# (glosses, audio) are taken from the first row.
+merge_translations() {
+  awk -F'\t' '
+    {
+      after = substr($0, index($0, "\t") + 1)
+      if (!($1 in english)) {
+        order[++count] = $1
+        english[$1] = $2
+        rest[$1] = (index(after, "\t") ? substr(after, index(after, "\t")) : "")
+      } else if ($2 != "" && index(" / " english[$1] " / ", " / " $2 " / ") == 0) {
+        english[$1] = english[$1] " / " $2
+      }
+    }
+    END {
+      for (i = 1; i <= count; i++) {
+        key = order[i]
+        if (english[key] == "" && rest[key] == "") {
+          print key
+        } else {
+          print key "\t" english[key] rest[key]
+        }
+      }
+    }
+  '
+}
+
+# --- Helper functions for reproducible shuffles ---
+# With SEED set, shuf reads its randomness from a keystream derived from the
+# seed (the approach suggested in the GNU coreutils manual), so the same seed
+# and input always give the same order.
+seeded_random_source() {
+  openssl enc -aes-256-ctr -pass pass:"$1" -nosalt </dev/zero 2>/dev/null
+}
+
+seeded_shuf() {
+  if [[ -n "$SEED" ]]; then
+    shuf --random-source=<(seeded_random_source "$SEED") "$@"
+  else
+    shuf "$@"
+  fi
+}
+
+# --- Helper function to spot consonant gradation slips ---
+# Checks whether word $2 is word $1 with exactly one gradation pair swapped
+# (kk/k, t/d, nt/nn, ...). If so, prints a short explanation; otherwise prints nothing.
+explain_gradation_error() {
+  local target="$1"
+  local selected="$2"
+  # strong:weak pairs; an empty weak grade means the consonant disappears (jalka -> jalan).
+  local gradation_pairs=("kk:k" "pp:p" "tt:t" "nk:ng" "mp:mm" "lt:ll" "nt:nn" "rt:rr" "lke:lje" "rke:rje" "t:d" "p:v" "k:")
+  local pair strong weak from to word other i
+  for pair in "${gradation_pairs[@]}"; do
+    strong="${pair%%:*}"
+    weak="${pair#*:}"
+    # Swap strong -> weak in either word and see if we land on the other one.
+    for word in "$target" "$selected"; do
+      if [[ "$word" == "$target" ]]; then other="$selected"; else other="$target"; fi
+      from="$strong"
+      to="$weak"
+      # Gradation never touches the first letter of a word (tie is not die).
+      for ((i = 1; i <= ${#word} - ${#from}; i++)); do
+        [[ "${word:i:${#from}}" == "$from" ]] || continue
+        # A lone k only disappears between vowels, or after l or r (jalka -> jalan),
+        # so kuin/uin and kala/ala are different words, not grades.
+        if [[ "$pair" == "k:" ]] && ! [[ "${word:i-1:1}" == [aeiouyäölr] && "${word:i+1:1}" == [aeiouyäö] ]]; then
+          continue
+        fi
+        if [[ "${word:0:i}${to}${word:i+${#from}}" == "$other" ]]; then
+          local target_grade="strong"
+          [[ "$word" != "$target" ]] && target_grade="weak"
+          echo -e "${C_YELLOW}Consonant gradation!${C_RESET} ${strong} ↔ ${weak:-∅}: the correct form has the ${target_grade} grade here."
+          echo "Finnish stems alternate between a strong and a weak consonant grade, e.g."
+          echo "kukka -> kukan, katu -> kadun, ranta -> rannan, jalka -> jalan."
+          return
+        fi
+      done
+    done
+  done
+}
+
+# --- Helper function to spot sound length slips ---
+# Checks whether word $2 is word $1 with one doubled letter made single, or
+# one single letter doubled (tuli/tuuli, kisa/kissa, tapan/tapaan), the same
+# undoubling finyap-pairs.bash builds its pairs from. If so, prints a short
+# explanation; otherwise prints nothing. Slips that are also a gradation pair
+# (kukan/kukkan) are left to explain_gradation_error.
+explain_length_error() {
+  local target="$1"
+  local selected="$2"
+  local long short i letter sound
+  if ((${#target} > ${#selected})); then
+    long="$target"
+    short="$selected"
+  else
+    long="$selected"
+    short="$target"
+  fi
+  ((${#long} == ${#short} + 1)) || return
+  for ((i = 0; i < ${#long} - 1; i++)); do
+    letter="${long:i:1}"
+    [[ "$letter" == "${long:i+1:1}" && "$letter" == [[:alpha:]] ]] || continue
+    [[ "${long:0:i}${long:i+1}" == "$short" ]] || continue
+    if [[ "$letter" == [aeiouyäöAEIOUYÄÖ] ]]; then
+      sound="vowel"
+    else
+      sound="consonant"
+    fi
+    if [[ "$long" == "$target" ]]; then
+      echo -e "${C_YELLOW}Sound length!${C_RESET} ${letter}${letter}, not ${letter}: the correct form has a long ${sound} here."
+    else
+      echo -e "${C_YELLOW}Sound length!${C_RESET} ${letter}, not ${letter}${letter}: the correct form has a short ${sound} here."
+    fi
+    echo "A doubled letter in Finnish is a long sound, and length alone can change"
+    echo "the word: tuli (fire) / tuuli (wind), kisa (contest) / kissa (cat)."
+    return
+  done
+}
+
+# --- Helper function for a gentle vowel harmony nudge (executed by bash -c) ---
+# If the target word sticks to one vowel class (back a/o/u or front ä/ö/y) and
+# the typed text already contains a vowel from the other class, prints a note.
+# Only called for the cipher mask, which shows the vowel classes anyway, and for
+# --mask none; with blank or first-letter it would give the vowel class away.
+# Compound words that mix both classes are left alone.
+vowel_harmony_note() {
+  local target="$1"
+  local typed="$2"
+  local target_back=false target_front=false
+  [[ "$target" == *[aou]* ]] && target_back=true
+  [[ "$target" == *[äöy]* ]] && target_front=true
+  if [[ "$target_back" == true && "$target_front" == false && "$typed" == *[äöy]* ]]; then
+    echo -e "${C_PINK}Vowel harmony:${C_RESET} this word takes back vowels (a, o, u), but you typed ä, ö or y."
+  elif [[ "$target_front" == true && "$target_back" == false && "$typed" == *[aou]* ]]; then
+    echo -e "${C_PINK}Vowel harmony:${C_RESET} this word takes front vowels (ä, ö, y), but you typed a, o or u."
+  fi
+}
+
+# --- Helper function to decide whether a stem can really carry a clitic ---
+# Pure suffix matching would mark la«kin» (lakki), ra«han» (raha) and aurin«ko».
+# A stem qualifies if it:
+# 1. is at least 3 letters long, or one of a few short words that take clitics
+#    (onko, sekin); three-letter stems ending in a consonant must be on that
+#    list too, so pan«kin» (pankki) and tan«ko» stay whole;
+# 2. ends the way a Finnish word form can, in a vowel, n or t, or is one of a few
+#    words ending in s (jos, myös); hal«pa», tur«kin» and maitopur«kin» don't;
+# 3. isn't an illative: maa«han» and pää«hän» are maahan and päähän;
+# 4. agrees with the clitic's vowel harmony: -ko, -pa, -han and -kaan need a
+#    back vowel (a, o, u) in the stem, -kö, -pä, -hän and -kään need none. -kin goes anywhere.
+# Finally, words that only look like stem + clitic, mostly the weak grade of a
+# k, kk or nk stem (lakin, pysäkin, jalkaan) or a noun ending in -ko or -pa
+# (aurinko, lupa), are listed in not_clitic_words. So is a compound ending in
+# one of them (ylioppilaslakin, kalastuslupa) if what comes before is at least
+# 4 letters and ends in a vowel, s, n or a hyphen, which keeps todella«kin»,
+# joita«kin» and olet«teko» apart from lakin, takin and teko.
+is_clitic_host() {
+  # Syllable dots from --syllables (on·ko) are not part of the stem.
+  local stem="${1//·/}"
+  stem="${stem,,}"
+  local clitic="$2"
+  local short_hosts=" on ei en et jo se me te he ne no mi ku sen hän nyt kun jon jot min jos myös "
+  local not_hosts=" tus " # tuskin is not tus + kin
+  local not_clitic_words="lakin pakin takin pankin penkin tankin lenkin linkin
+    merkin pitkin tutkin leikin pysäkin apteekin musiikin piknikin lemmikin shekin mikin
+    aurinko vahinko tauko jatko katko koko jako teko sisko isosisko
+    unohtako poistako lähettäkö aikaan työaikaan jalkaan matkaan ruokaan kuninkaan pitkään
+    pelkään lupa tupa tapa halpa leipä nauhan kauhan vanhan turhan nahan päänahan kuhan"
+  if [[ ${#stem} -lt 3 || (${#stem} -eq 3 && "$stem" != *[aeiouyäö]) ]] &&
+    [[ "$short_hosts" != *" $stem "* ]]; then
+    return 1
+  fi
+  if [[ "$not_hosts" == *" $stem "* ]]; then
+    return 1
+  fi
+  if [[ "$stem" != *[aeiouyäönt] && "$short_hosts" != *" $stem "* ]]; then
+    return 1
+  fi
+  if [[ ("$clitic" == "han" && "$stem" == *aa) || ("$clitic" == "hän" && "$stem" == *ää) ]]; then
+    return 1
+  fi
+  local word prefix
+  for word in $not_clitic_words; do
+    [[ "$stem$clitic" == *"$word" ]] || continue
+    prefix="${stem%"${word%"$clitic"}"}"
+    if [[ -z "$prefix" || (${#prefix} -ge 4 && "$prefix" == *[aeiouyäösn-]) ]]; then
+      return 1
+    fi
+  done
+  case "$clitic" in
+  kin) return 0 ;;
+  *[äö]*) [[ "$stem" != *[aou]* ]] ;;
+  *) [[ "$stem" == *[aou]* ]] ;;
+  esac
+}
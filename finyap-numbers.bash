@@ -0,0 +1,160 @@
+#!/bin/bash
+
+# finyap-numbers: Numbers drill scenario generator
+# Writes a TSV scenario of random numbers spelled out in Finnish, with the
+# digits as the "English", so numerals can be drilled like any other scenario.
+
+FINYAP_VERSION="0.0.2"
+COUNT=100   # How many numbers to generate
+MAX=9999    # Largest number to draw from
+SEED=""     # Fixed seed for the random numbers, see --seed
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options]
+
+Generate a finyap scenario for practising Finnish numerals.
+
+Each line has a random number spelled out in Finnish (in the nominative,
+as you would count or read out a price), the number in digits, and the
+parts it is built from as a gloss. Write it to a file and play it:
+
+  bash $(basename "$0") > scenarios/numbers.tsv
+  bash finyap.bash --input scenarios/numbers.tsv
+
+Options:
+  -h, --help      Show this help message and exit.
+      --version   Show script version and exit.
+  --count N       How many numbers to generate (default: $COUNT).
+  --max N         Largest number to use (default: $MAX, at most 999999999).
+  --seed SEED     Generate the same numbers every time. Requires openssl.
+EOF
+}
+
+# --- Argument Parsing ---
+while [[ $# -gt 0 ]]; do
+  key="$1"
+  case $key in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  --count)
+    if ! [[ "$2" =~ ^[0-9]+$ ]]; then
+      echo "Error: --count requires a number." >&2
+      exit 1
+    fi
+    COUNT="$2"
+    shift 2
+    ;;
+  --max)
+    if ! [[ "$2" =~ ^[0-9]+$ && "$2" -le 999999999 ]]; then
+      echo "Error: --max requires a number up to 999999999." >&2
+      exit 1
+    fi
+    MAX="$2"
+    shift 2
+    ;;
+  --seed)
+    if [[ -z "$2" ]]; then
+      echo "Error: --seed option requires a value." >&2
+      exit 1
+    fi
+    SEED="$2"
+    shift 2
+    ;;
+  -*)
+    echo "Error: Unknown option '$1'." >&2
+    exit 1
+    ;;
+  *)
+    shift
+    ;;
+  esac
+done
+
+if [[ -n "$SEED" ]] && ! command -v openssl &>/dev/null; then
+  echo "Error: --seed requires openssl to generate a reproducible random stream." >&2
+  exit 1
+fi
+
+# --- Spelling out numbers ---
+ONES=("" "yksi" "kaksi" "kolme" "neljä" "viisi" "kuusi" "seitsemän" "kahdeksan" "yhdeksän")
+
+# Spells out 1-999 as one word, with "+" between the parts it is built from.
+# 245 becomes "kaksisataa+neljäkymmentä+viisi".
+spell_below_thousand() {
+  local n="$1"
+  local hundreds=$((n / 100))
+  local rest=$((n % 100))
+  local parts=()
+  if [[ $hundreds -eq 1 ]]; then
+    parts+=("sata")
+  elif [[ $hundreds -gt 1 ]]; then
+    parts+=("${ONES[hundreds]}sataa")
+  fi
+  if [[ $rest -ge 20 ]]; then
+    parts+=("${ONES[rest / 10]}kymmentä")
+    rest=$((rest % 10))
+  fi
+  if [[ $rest -eq 10 ]]; then
+    parts+=("kymmenen")
+  elif [[ $rest -gt 10 ]]; then
+    parts+=("${ONES[rest - 10]}toista")
+  elif [[ $rest -gt 0 ]]; then
+    parts+=("${ONES[rest]}")
+  fi
+  local IFS="+"
+  echo "${parts[*]}"
+}
+
+# Spells out 0-999999999 in the nominative, again with "+" between the parts.
+# Everything below a million is written as one word; miljoona(a) stands apart.
+spell_number() {
+  local n="$1"
+  if [[ $n -eq 0 ]]; then
+    echo "nolla"
+    return
+  fi
+  local millions=$((n / 1000000))
+  local thousands=$((n / 1000 % 1000))
+  local rest=$((n % 1000))
+  local words=() parts=()
+  if [[ $millions -eq 1 ]]; then
+    words+=("miljoona")
+  elif [[ $millions -gt 1 ]]; then
+    words+=("$(spell_below_thousand "$millions")" "miljoonaa")
+  fi
+  if [[ $thousands -eq 1 ]]; then
+    parts+=("tuhat")
+  elif [[ $thousands -gt 1 ]]; then
+    parts+=("$(spell_below_thousand "$thousands")+tuhatta")
+  fi
+  if [[ $rest -gt 0 ]]; then
+    parts+=("$(spell_below_thousand "$rest")")
+  fi
+  if [[ ${#parts[@]} -gt 0 ]]; then
+    words+=("$(IFS="+" && echo "${parts[*]}")")
+  fi
+  echo "${words[*]}"
+}
+
+# --- Drawing the numbers ---
+random_numbers() {
+  if [[ -n "$SEED" ]]; then
+    shuf -r -n "$COUNT" -i 0-"$MAX" --random-source=<(openssl enc -aes-256-ctr -pass pass:"$SEED" -nosalt </dev/zero 2>/dev/null)
+  else
+    shuf -r -n "$COUNT" -i 0-"$MAX"
+  fi
+}
+
+echo "# Finnish numerals, generated by $(basename "$0") --count $COUNT --max $MAX"
+random_numbers | while read -r number; do
+  spelled=$(spell_number "$number")
+  printf '%s\t%s\t%s\n' "${spelled//+/}" "$number" "${spelled//+/ + }"
+done
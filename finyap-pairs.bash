@@ -0,0 +1,180 @@
+#!/bin/bash
+
+# finyap-pairs: Minimal pair drills
+# Finds words in the scenario files that differ only in the length of one
+# sound (tuli/tuuli/tulli, tapan/tapaan) and asks which one a sentence needs.
+
+FINYAP_VERSION="0.0.2"
+ROUNDS=10 # How many questions to ask, see --count
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options] [FILE|DIR ...]
+
+Drill Finnish minimal pairs: words that differ only in a short or long
+vowel or consonant, like tuli (fire), tuuli (wind) and tulli (customs).
+
+Each question shows the English and the Finnish sentence with one word
+blanked out, and two candidates that differ in one sound's length. Press
+1 or 2 to choose. The words you mixed up are listed at the end.
+
+Pairs and sentences come from the TSV files given (directories are searched
+for *.tsv files). With no arguments, uses the directories in
+\$FINYAP_SCENARIO_DIRS (colon-separated), or scenarios/ if that is unset.
+
+Options:
+  -h, --help      Show this help message and exit.
+      --version   Show script version and exit.
+  --count N       Ask N questions (default: $ROUNDS).
+EOF
+}
+
+# --- Argument Parsing ---
+targets=()
+while [[ $# -gt 0 ]]; do
+  key="$1"
+  case $key in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  --count)
+    if ! [[ "$2" =~ ^[0-9]+$ ]]; then
+      echo "Error: --count requires a number." >&2
+      exit 1
+    fi
+    ROUNDS="$2"
+    shift 2
+    ;;
+  -*)
+    echo "Error: Unknown option '$1'." >&2
+    exit 1
+    ;;
+  *)
+    targets+=("$1")
+    shift
+    ;;
+  esac
+done
+
+if [[ ${#targets[@]} -eq 0 ]]; then
+  IFS=':' read -r -a targets <<<"${FINYAP_SCENARIO_DIRS:-scenarios/}"
+fi
+
+# --- ANSI Colors ---
+C_RESET=$'\033[0m'
+C_GREEN=$'\033[1;32m'
+C_YELLOW=$'\033[1;33m'
+C_RED=$'\033[1;31m'
+C_GREY=$'\033[2m'
+
+# --- Scenario file reading (normalize_tsv, merge_translations, to_nfc) ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
+# --- Collect the files to read ---
+tsv_files=()
+for target in "${targets[@]}"; do
+  if [[ -d "$target" ]]; then
+    while IFS= read -r file; do
+      tsv_files+=("$file")
+    done < <(find "$target" -name "*.tsv" -type f | sort)
+  elif [[ -f "$target" ]]; then
+    tsv_files+=("$target")
+  else
+    echo "Error: '$target' is not a file or directory." >&2
+    exit 1
+  fi
+done
+
+if [[ ${#tsv_files[@]} -eq 0 ]]; then
+  echo "Error: No .tsv files found in: ${targets[*]}" >&2
+  exit 1
+fi
+
+echo "Looking for minimal pairs in ${#tsv_files[@]} file(s)..."
+
+# Every sentence, and every word with the numbers of the sentences it is in.
+# GNU sed's \L and [[:punct:]] handle ä, ö and curly quotes in a UTF-8 locale.
+mapfile -t sentences < <(for file in "${tsv_files[@]}"; do normalize_tsv <"$file"; done)
+declare -A sentences_with
+while IFS=$'\t' read -r number word; do
+  sentences_with["$word"]+=" $number"
+done < <(printf '%s\n' "${sentences[@]}" | cut -f1 |
+  awk '{ for (i = 1; i <= NF; i++) print NR - 1 "\t" $i }' |
+  sed -E 's/\t[[:punct:]]+/\t/; s/[[:punct:]]+$//; s/.*/\L&/' |
+  grep -v $'\t$')
+
+# A pair is a word with a doubled letter and the word you get by undoubling it.
+pairs=()
+while IFS= read -r word; do
+  for ((i = 0; i < ${#word} - 1; i++)); do
+    if [[ "${word:i:1}" == "${word:i+1:1}" && "${word:i:1}" == [[:alpha:]] ]]; then
+      short="${word:0:i}${word:i+1}"
+      if [[ -n "${sentences_with[$short]}" ]]; then
+        pairs+=("$short $word")
+      fi
+    fi
+  done
+done < <(printf '%s\n' "${!sentences_with[@]}" | grep -E '([[:alpha:]])\1')
+
+if [[ ${#pairs[@]} -eq 0 ]]; then
+  echo "No minimal pairs found. Try more scenario files." >&2
+  exit 1
+fi
+echo "Found ${#pairs[@]} minimal pairs."
+echo ""
+
+score=0
+asked=0
+mixed_up=()
+for ((round = 1; round <= ROUNDS; round++)); do
+  read -r short long <<<"$(printf '%s\n' "${pairs[@]}" | shuf -n 1)"
+  # Ask about a sentence with either word in it, so both sides of the pair come up.
+  answer=$(printf '%s\n%s\n' "$short" "$long" | shuf -n 1)
+  number=$(echo ${sentences_with[$answer]} | tr ' ' '\n' | shuf -n 1)
+  IFS=$'\t' read -r finnish english _ <<<"${sentences[number]}"
+
+  # Blank out the answer, keeping any punctuation around it.
+  blanked=()
+  read -r -a tokens <<<"$finnish"
+  for token in "${tokens[@]}"; do
+    bare=$(echo "$token" | sed -E 's/^[[:punct:]]+//; s/[[:punct:]]+$//')
+    if [[ "${bare,,}" == "$answer" ]]; then
+      token="${token/"$bare"/_____}"
+    fi
+    blanked+=("$token")
+  done
+
+  mapfile -t candidates < <(printf '%s\n%s\n' "$short" "$long" | shuf)
+  echo -e "${C_GREY}[${round}/${ROUNDS}]${C_RESET} ${english}"
+  echo "        ${blanked[*]}"
+  echo -e "        1) ${C_YELLOW}${candidates[0]}${C_RESET}    2) ${C_YELLOW}${candidates[1]}${C_RESET}"
+  read -r -p "$ " choice </dev/tty
+  case "$choice" in
+  1) chosen="${candidates[0]}" ;;
+  2) chosen="${candidates[1]}" ;;
+  q* | Q*) break ;;
+  *) chosen="" ;;
+  esac
+
+  asked=$((asked + 1))
+  if [[ "$chosen" == "$answer" ]]; then
+    score=$((score + 1))
+    echo -e "${C_GREEN}Right!${C_RESET} ${finnish}"
+  else
+    echo -e "${C_RED}Not quite.${C_RESET} ${finnish}"
+    mixed_up+=("${answer} (not ${chosen:-?})")
+  fi
+  echo ""
+done
+
+echo "Score: ${score}/${asked}"
+if [[ ${#mixed_up[@]} -gt 0 ]]; then
+  echo "Words you mixed up:"
+  printf '  %s\n' "${mixed_up[@]}" | sort | uniq -c | sort -rn
+fi
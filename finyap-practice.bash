@@ -48,7 +48,13 @@ print_finnish_flag() {
 
 clean_word() {
   local word="$1"
-  word=$(echo "$word" | tr '[:upper:]' '[:lower:]')
+  # Each one is replaced on its own: a [’ʼ] bracket would match their single
+  # bytes under the C locale and mangle ä and ö along with them.
+  word="${word//’/\'}"
+  word="${word//ʼ/\'}"
+  word="${word//‐/-}"
+  word="${word//‑/-}"
+  word="${word,,}"
   word=$(echo "$word" | sed -E 's/^[[:punct:].,!?;:]+|[[:punct:].,!?;:]+$//g')
   echo "$word"
 }
@@ -62,20 +68,27 @@ cipher_word() {
     -e 's/[bcdfghjklmnpqrstvwxzBCDFGHJKLMNPQRSTVWXZ]/x/g'
 }
 
+# --- Helpers shared with the other scripts, see finyap-lib.bash ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
 add_clitic_markers() {
   local word_to_process="$1"
   local temp_word="$word_to_process"
   local processed_clitics_part=""
-  local clitics_list=("kaan" "kään" "kin" "han" "hän" "ko" "kö" "pa" "pä")
+  local clitics_list=("kaan:1" "kään:1" "kin:1" "ko:1" "kö:1" "pa:2" "pä:2" "han:3" "hän:3")
+  local outer_rank=4
   while true; do
     local found_in_pass=false
-    for clitic in "${clitics_list[@]}"; do
-      if [[ "$(echo "$temp_word" | tr '[:upper:]' '[:lower:]')" == *"$clitic" ]]; then
+    for entry in "${clitics_list[@]}"; do
+      local clitic="${entry%%:*}"
+      local rank="${entry#*:}"
+      if [[ "$rank" -lt "$outer_rank" && "${temp_word,,}" == *"$clitic" ]]; then
         local suffix_len=${#clitic}
         local original_clitic_case="${temp_word: -$suffix_len}"
-        if [[ "$(echo "$original_clitic_case" | tr '[:upper:]' '[:lower:]')" == "$clitic" ]]; then
+        if is_clitic_host "${temp_word::-$suffix_len}" "$clitic"; then
           processed_clitics_part="«${original_clitic_case}»${processed_clitics_part}"
           temp_word="${temp_word::-$suffix_len}"
+          outer_rank="$rank"
           found_in_pass=true
           break
         fi
@@ -92,7 +105,7 @@ run_fzf_preview() {
   local current_fzf_query="$1"
   local current_fzf_selection="$2"
   local query_for_comparison
-  query_for_comparison=$(echo "$current_fzf_query" | tr '[:upper:]' '[:lower:]' | sed -E 's/^[[:punct:].,!?;:]+|[[:punct:].,!?;:]+$//g')
+  query_for_comparison=$(clean_word "$current_fzf_query")
   local selection_for_comparison="$current_fzf_selection"
 
   echo -e "${C_BLUE}finyap v${FINYAP_VERSION} - $(date +%Y-%m-%d)${C_RESET}"
@@ -108,6 +121,7 @@ run_fzf_preview() {
   else
     echo "Typed so far:  ${C_RED}${query_for_comparison}${C_RESET}"
   fi
+  vowel_harmony_note "$FZF_PREVIEW_TARGET_WORD" "$query_for_comparison"
   echo ""
   echo -e "${C_GREY}Found a bug? Report it at https://github.com/hiAndrewQuinn/finyap/issues/new?labels=bug${C_RESET}"
   if [[ -n "$selection_for_comparison" && "$selection_for_comparison" == "$FZF_PREVIEW_TARGET_WORD" ]]; then
@@ -121,9 +135,137 @@ run_fzf_preview() {
 }
 
 # Export functions and variables needed by the fzf preview subshell
-export -f run_fzf_preview print_finnish_flag
+export -f run_fzf_preview print_finnish_flag vowel_harmony_note clean_word
 export C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK C_BG_HIGHLIGHT_YELLOW FINYAP_VERSION C_BLUE C_GREY
 
+# Counts the sentences in each scenario file read on stdin, one path per line,
+# as normalize_tsv | merge_translations would leave them (one per distinct
+# Finnish sentence). Prints "count<TAB>file" lines. Every file goes through a
+# single to_nfc, so a long selection doesn't start python3 once per file.
+scenario_sizes() {
+  local files=() i
+  mapfile -t files
+  for i in "${!files[@]}"; do
+    unwrap_tsv <"${files[i]}" | sed "s/^/$i\t/"
+  done | to_nfc | awk -F'\t' -v files="${#files[@]}" '
+    !seen[$1 FS $2]++ { count[$1]++ }
+    END { for (i = 0; i < files; i++) print count[i] + 0 }
+  ' | paste - <(printf '%s\n' "${files[@]}")
+}
+
+# Splits a session total across scenario files, read on stdin as the
+# "count<TAB>file" lines of scenario_sizes.
+# $1 is the total, $2 is "proportional" (by sentence count) or "equal".
+# Prints "count<TAB>file" lines; leftovers go to the largest remainders.
+allocate_session_counts() {
+  local total="$1"
+  local spread="$2"
+  awk -F'\t' -v total="$total" -v spread="$spread" '
+    { size[NR] = $1; file[NR] = $2; sum += $1 }
+    END {
+      for (i = 1; i <= NR; i++) {
+        share = (spread == "equal") ? total / NR : (sum > 0 ? total * size[i] / sum : 0)
+        count[i] = int(share)
+        frac[i] = share - count[i]
+        given += count[i]
+      }
+      while (given < total) {
+        best = 0
+        for (i = 1; i <= NR; i++) {
+          if (frac[i] >= 0 && (best == 0 || frac[i] > frac[best])) best = i
+        }
+        if (best == 0) break
+        count[best]++
+        frac[best] = -1
+        given++
+      }
+      for (i = 1; i <= NR; i++) print count[i] "\t" file[i]
+    }
+  '
+}
+
+# One line of session-wide progress: elapsed time, sentences done and to go,
+# and the share completed correctly so far.
+session_status() {
+  local elapsed=$((SECONDS - session_start))
+  local accuracy="-"
+  if [[ "$sentences_done" -gt 0 ]]; then
+    accuracy="$((sentences_correct * 100 / sentences_done))%"
+  fi
+  printf 'Session: %02d:%02d elapsed | %d/%d sentences, %d to go | %s correct\n' \
+    "$((elapsed / 60))" "$((elapsed % 60))" "$sentences_done" "$session_total" \
+    "$((session_total - sentences_done))" "$accuracy"
+}
+
+# Sentences missed in any session wait in RECOVERY_FILE, one "file<TAB>line" row
+# each, until they are answered correctly, so quitting early never loses them.
+remember_missed_sentence() {
+  [[ "$EPHEMERAL" == true ]] && return
+  local entry="$1"$'\t'"$2"
+  if ! grep -qxF -- "$entry" "$RECOVERY_FILE" 2>/dev/null; then
+    echo "$entry" >>"$RECOVERY_FILE"
+  fi
+}
+
+forget_missed_sentence() {
+  [[ "$EPHEMERAL" == true ]] && return
+  local entry="$1"$'\t'"$2"
+  if [[ -f "$RECOVERY_FILE" ]]; then
+    grep -vxF -- "$entry" "$RECOVERY_FILE" >"$RECOVERY_FILE.tmp"
+    mv "$RECOVERY_FILE.tmp" "$RECOVERY_FILE"
+  fi
+}
+
+# Builds the fzf word list from every Finnish sentence of a normalized scenario on stdin.
+scenario_word_list() {
+  cut -f1 | tr -s '[:space:]' '\n' |
+    while IFS= read -r word_token; do
+      cleaned=$(clean_word "$word_token")
+      if [[ -n "$cleaned" ]]; then echo "$cleaned"; fi
+    done | sort -u | grep -v '^$'
+}
+
+# Plays "file<TAB>line" rows read on stdin, each with the word list of the
+# scenario it came from. $1 is the number of rows, for the round counter.
+declare -A queued_word_lists
+play_queued_sentences() {
+  local total="$1"
+  local file line_for_round
+  local round_num=0
+  while IFS=$'\t' read -r file line_for_round; do
+    round_num=$((round_num + 1))
+    if [[ -z "${queued_word_lists["$file"]}" ]]; then
+      queued_word_lists["$file"]=$(normalize_tsv <"$file" | merge_translations | scenario_word_list)
+    fi
+    run_game_round "$file" "$round_num" "$total" "${queued_word_lists["$file"]}" "$line_for_round"
+  done
+}
+
+# Every sentence of the selected scenarios as "file<TAB>line" rows, once per
+# distinct Finnish sentence and scenario. Every file goes through a single
+# to_nfc, as in scenario_sizes.
+scenario_corpus_rows() {
+  local files=() i
+  mapfile -t files
+  for i in "${!files[@]}"; do
+    unwrap_tsv <"${files[i]}" | sed "s/^/$i\t/"
+  done | to_nfc | SCENARIO_FILES="$(printf '%s\n' "${files[@]}")" awk -F'\t' '
+    BEGIN { split(ENVIRON["SCENARIO_FILES"], names, "\n") }
+    !seen[$1 FS $2]++ { print names[$1 + 1] substr($0, length($1) + 1) }
+  '
+}
+
+# Prints up to two rows of scenario_corpus_rows (in $scenario_corpus) whose
+# Finnish sentence contains the word $1, other than the sentence $2, to play
+# again later in the session after $1 was missed.
+similar_sentence_rows() {
+  local word="$1"
+  local missed="$2"
+  echo "$scenario_corpus" | cut -f2 | grep -niwF -- "$word" | cut -d: -f1 |
+    awk -F'\t' -v missed="$missed" 'NR == FNR { wanted[$1]; next } FNR in wanted && $2 != missed' - <(echo "$scenario_corpus") |
+    seeded_shuf -n 2
+}
+
 # --- MAIN GAME ROUND FUNCTION ---
 # This function contains the logic from the main body of finyap.bash
 run_game_round() {
@@ -134,8 +276,9 @@ run_game_round() {
   local random_line="$5"       # Now passed as an argument
 
   clear
-  echo "practice-scenarios: [${current_tsv_index}/${total_tsv_files}] ${scenario_file}"
+  echo "practice-scenarios: ${session_phase:-[${current_tsv_index}/${total_tsv_files}]} ${scenario_file}"
   echo "practice-scenarios: [${current_round}/${total_rounds}]"
+  echo -e "${C_BLUE}$(session_status)${C_RESET}"
   echo ""
   echo "finyap v${FINYAP_VERSION} - https://github.com/hiAndrewQuinn/finyap - https://finbug.xyz/ - https://andrew-quinn.me/"
   echo "Found a bug? Report it at https://github.com/hiAndrewQuinn/finyap/issues/new?labels=bug"
@@ -143,10 +286,12 @@ run_game_round() {
 
   finnish_sentence=$(echo "$random_line" | cut -f1)
   english_translation=$(echo "$random_line" | cut -f2)
+  sentence_glosses=$(echo "$random_line" | cut -f3)
 
   IFS=' ' read -r -a words_in_sentence <<<"$finnish_sentence"
   if [[ ${#words_in_sentence[@]} -eq 0 ]]; then
     echo "Warning: Skipping empty sentence from '$scenario_file'."
+    sentences_done=$((sentences_done + 1))
     sleep 1
     return
   fi
@@ -197,6 +342,7 @@ run_game_round() {
       fzf --ignore-case --layout=reverse --border \
         --prompt="   ${ciphered_current} " \
         --preview="bash -c 'run_fzf_preview \"\$1\" \"\$2\"' -- {q} {}" \
+        --header="$(session_status)" \
         --header-first \
         --preview-window="up,80%,wrap,border-sharp")
 
@@ -232,11 +378,36 @@ run_game_round() {
       echo -e "${C_RED}Not quite. Game over for this round.${C_RESET}"
       echo -e "You selected:         ${C_RED}${selected_word_from_fzf}${C_RESET}"
       echo -e "The correct word was: ${C_GREEN}${target_word_original}${C_RESET}"
+      gradation_hint=$(explain_gradation_error "$target_word_for_matching" "$selected_word_from_fzf")
+      if [[ -z "$gradation_hint" ]]; then
+        gradation_hint=$(explain_length_error "$target_word_for_matching" "$selected_word_from_fzf")
+      fi
+      if [[ -n "$gradation_hint" ]]; then
+        echo -e "$gradation_hint"
+      fi
       game_failed=true
       break
     fi
   done
 
+  sentences_done=$((sentences_done + 1))
+  if [[ "$game_failed" != true ]]; then
+    sentences_correct=$((sentences_correct + 1))
+    forget_missed_sentence "$scenario_file" "$random_line"
+  else
+    remember_missed_sentence "$scenario_file" "$random_line"
+    session_missed+=("$scenario_file"$'\t'"$random_line")
+  fi
+
+  # Other sentences with the missed word can be added to this session.
+  similar_rows=""
+  if [[ "$game_failed" == true && -n "$target_word_for_matching" ]]; then
+    if [[ -z "$scenario_corpus" ]]; then
+      scenario_corpus=$(echo "$files_to_process" | scenario_corpus_rows)
+    fi
+    similar_rows=$(similar_sentence_rows "$target_word_for_matching" "$finnish_sentence")
+  fi
+
   echo ""
   echo "============================================================"
   if [[ "$game_failed" == true ]]; then
@@ -248,6 +419,10 @@ run_game_round() {
   echo "The full sentence was:"
   echo "Finnish: $finnish_sentence"
   echo "English: $english_translation"
+  if [[ -n "$sentence_glosses" ]]; then
+    echo -e "Glosses: ${C_GREY}${sentence_glosses}${C_RESET}"
+  fi
+  echo -e "${C_BLUE}$(session_status)${C_RESET}"
   echo "============================================================"
   echo ""
 
@@ -255,13 +430,23 @@ run_game_round() {
   echo "Review your answer."
   echo "- Press Enter to continue."
   echo "- Enter 'q' to (q)uit."
-  echo "- Enter 'c' to save this sentence to check.csv."
+  if [[ "$EPHEMERAL" != true ]]; then
+    echo "- Enter 'c' to save this sentence to check.csv."
+  fi
+  if [[ -n "$similar_rows" ]]; then
+    echo "- Enter 'a' to (a)dd $(echo "$similar_rows" | wc -l | xargs) more sentence(s) with '${target_word_for_matching}' to the end of this session."
+  fi
   read -p "$ " user_input </dev/tty
 
-  if [[ "$user_input" == "c" || "$user_input" == "C" ]]; then
+  if [[ "$EPHEMERAL" != true && ("$user_input" == "c" || "$user_input" == "C") ]]; then
     echo "\"$scenario_file\",\"$english_translation\",\"$finnish_sentence\"" >>check.csv
     echo "Entry saved to: $(realpath check.csv)"
     sleep 1
+  elif [[ -n "$similar_rows" && ("$user_input" == "a" || "$user_input" == "A") ]]; then
+    mapfile -t -O "${#session_extra[@]}" session_extra <<<"$similar_rows"
+    session_total=$((session_total + $(echo "$similar_rows" | wc -l)))
+    echo "Added. They come after the selected scenarios."
+    sleep 1
   elif [[ "$user_input" == "q"* || "$user_input" == "Q"* ]]; then
     echo "Exiting."
     # MODIFICATION 2.1: This exit command will now terminate the whole script
@@ -272,11 +457,112 @@ run_game_round() {
 
 # --- SCRIPT ENTRY POINT (from practice-scenarios.bash) ---
 
+SEED=""           # Seed for reproducible shuffles, see --seed
+RECOVERY_PASSES=3 # How often this session's misses are replayed, see --recovery-passes
+EPHEMERAL=false   # Don't read or write any state files, see --ephemeral
+ORDER=blocked     # How scenarios are interleaved: blocked, round-robin or mixed, see --order
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options]
+
+Play finyap through many scenario files in one session. It asks how many
+sentences to play per scenario (or in total, spread across them) and which
+scenarios to use, then plays them with a progress line, one scenario after
+the other unless --order says otherwise.
+After a missed word, 'a' adds up to two other sentences with it to the end
+of the session.
+
+Scenarios are the *.tsv files in the directories in \$FINYAP_SCENARIO_DIRS
+(colon-separated), or in scenarios/ if that is unset.
+
+Options:
+  -h, --help      Show this help message and exit.
+      --version   Show script version and exit.
+  --seed SEED     Make the scenario order and the sentences picked
+                  reproducible: the same seed gives the same session.
+  --recovery-passes N
+                  At the end, replay the sentences missed this session,
+                  again and again until a pass is clean or N passes have
+                  run (default: $RECOVERY_PASSES, 0 turns it off).
+  --ephemeral     Neither read nor write any of the files below, for
+                  trying out scenarios without touching your history.
+  --order ORDER   The order sentences from different scenarios come in:
+                    blocked      one scenario after the other (default)
+                    round-robin  one sentence from each scenario in turn
+                    mixed        all of them shuffled together
+
+Files, in the current directory:
+  last_session.txt    The last count and scenario selection, offered
+                      again next time.
+  recovery_queue.tsv  Sentences missed in any session, one "file<TAB>line"
+                      row each, until they are answered correctly. They
+                      can be played first in the next session.
+  so_far.txt          The scenarios selected for the current session.
+  check.csv           Sentences you marked with 'c' after a round, to
+                      check or report later.
+EOF
+}
+
+while [[ $# -gt 0 ]]; do
+  case "$1" in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  --ephemeral)
+    EPHEMERAL=true
+    shift
+    ;;
+  --recovery-passes)
+    if ! [[ "$2" =~ ^[0-9]+$ ]]; then
+      echo "Error: --recovery-passes option requires a number." >&2
+      exit 1
+    fi
+    RECOVERY_PASSES="$2"
+    shift 2
+    ;;
+  --order)
+    case "$2" in
+    blocked | round-robin | mixed)
+      ORDER="$2"
+      shift 2
+      ;;
+    *)
+      echo "Error: --order must be one of: blocked, round-robin, mixed." >&2
+      exit 1
+      ;;
+    esac
+    ;;
+  --seed)
+    if [[ -z "$2" ]]; then
+      echo "Error: --seed option requires a value." >&2
+      exit 1
+    fi
+    SEED="$2"
+    shift 2
+    ;;
+  *)
+    echo "Error: Unknown option '$1'." >&2
+    exit 1
+    ;;
+  esac
+done
+
 # Sanity check for fzf
 if ! command -v fzf &>/dev/null; then
   echo "Error: fzf is not installed. It's required for file selection."
   exit 1
 fi
+if [[ -n "$SEED" ]] && ! command -v openssl &>/dev/null; then
+  echo "Error: --seed requires openssl to generate a reproducible random stream."
+  exit 1
+fi
 
 # MODIFICATION 1.1: Add a trap to clean up temporary files on exit
 trap 'rm -f /dev/shm/finyap_practice_*.tsv' EXIT
@@ -285,31 +571,90 @@ echo "============================================================"
 echo " Finnish Yap Practice Scenarios (Refactored)"
 echo "============================================================"
 echo ""
-read -p "Enter number of reviews per scenario [10]: " user_loop_count
-loop_count=${user_loop_count:-10}
+
+# The last session's count and scenario selection are kept here between runs:
+# the first line is the count, every following line a scenario file.
+LAST_SESSION_FILE="last_session.txt"
+RECOVERY_FILE="recovery_queue.tsv" # Missed sentences, see remember_missed_sentence
+default_loop_count=10
+last_selection=""
+if [[ "$EPHEMERAL" == true ]]; then
+  echo "Ephemeral session: nothing will be saved."
+elif [[ -f "$LAST_SESSION_FILE" ]]; then
+  default_loop_count=$(head -n 1 "$LAST_SESSION_FILE")
+  if ! [[ "$default_loop_count" =~ ^[0-9]+$ ]]; then
+    default_loop_count=10
+  fi
+  # Drop scenarios that have been removed or renamed since.
+  last_selection=$(tail -n +2 "$LAST_SESSION_FILE" | while IFS= read -r file; do
+    if [[ -f "$file" ]]; then echo "$file"; fi
+  done)
+fi
+
+read -p "Enter number of reviews per scenario [${default_loop_count}]: " user_loop_count
+loop_count=${user_loop_count:-$default_loop_count}
 
 if ! [[ "$loop_count" =~ ^[0-9]+$ ]]; then
-  echo "Invalid input. Defaulting to 10."
-  loop_count=10
+  echo "Invalid input. Defaulting to ${default_loop_count}."
+  loop_count=$default_loop_count
+fi
+
+# With many scenarios selected, a per-scenario count adds up fast, so the
+# number can instead be a total for the whole session.
+read -p "Count that per (s)cenario, or as a (t)otal across all scenarios? [s]: " sizing_mode
+spread_mode=""
+if [[ "$sizing_mode" == "t"* || "$sizing_mode" == "T"* ]]; then
+  read -p "Spread the total (p)roportionally to scenario size, or (e)qually? [p]: " spread_mode
+  if [[ "$spread_mode" == "e"* || "$spread_mode" == "E"* ]]; then
+    spread_mode="equal"
+  else
+    spread_mode="proportional"
+  fi
 fi
 echo ""
 
-all_tsv_files=$(find scenarios/ -name "*.tsv" -type f | shuf)
+# Scenario directories are read from FINYAP_SCENARIO_DIRS, colon-separated
+# like PATH (e.g. "scenarios/:$HOME/finnish/shared"), defaulting to scenarios/.
+IFS=':' read -r -a scenario_dirs <<<"${FINYAP_SCENARIO_DIRS:-scenarios/}"
+for dir in "${scenario_dirs[@]}"; do
+  if [[ ! -d "$dir" ]]; then
+    echo "Warning: Scenario directory '$dir' not found. Skipping it."
+  fi
+done
+all_tsv_files=$(find "${scenario_dirs[@]}" -name "*.tsv" -type f 2>/dev/null | sort | seeded_shuf)
 total_available_files=$(echo "$all_tsv_files" | wc -l | xargs)
 
 if [ "$total_available_files" -eq 0 ]; then
-  echo "Error: No .tsv files found in the 'scenarios/' directory."
+  echo "Error: No .tsv files found in: ${scenario_dirs[*]}"
   exit 1
 fi
 
+# The same file name in two directories is fine; the full path tells them apart.
+duplicate_names=$(echo "$all_tsv_files" | xargs -d '\n' -n 1 basename | sort | uniq -d)
+if [[ -n "$duplicate_names" ]]; then
+  echo "Note: These scenarios exist in more than one directory; every copy is listed:"
+  echo "$duplicate_names" | sed 's/^/  /'
+fi
+
 echo "Found ${total_available_files} scenarios."
-read -p "Process all of them? [Y/n]: " process_all
+if [[ -n "$last_selection" ]]; then
+  echo "Last time you picked $(echo "$last_selection" | wc -l | xargs) of them."
+  read -p "Process all of them? [Y/n], reuse your (l)ast selection, or (f)orget it: " process_all
+else
+  read -p "Process all of them? [Y/n]: " process_all
+fi
 echo ""
 
 files_to_process=""
-if [[ -z "$process_all" || "$process_all" == "y" || "$process_all" == "Y" ]]; then
+if [[ -n "$last_selection" && ("$process_all" == "l" || "$process_all" == "L") ]]; then
+  files_to_process=$(echo "$last_selection" | seeded_shuf)
+elif [[ -z "$process_all" || "$process_all" == "y" || "$process_all" == "Y" ]]; then
   files_to_process="$all_tsv_files"
 else
+  if [[ -n "$last_selection" && ("$process_all" == "f" || "$process_all" == "F") ]]; then
+    rm -f "$LAST_SESSION_FILE"
+    echo "Forgot the last selection."
+  fi
   echo "Use TAB to select/deselect files, then press Enter to confirm."
   sleep 1
   files_to_process=$(echo "$all_tsv_files" | fzf \
@@ -322,58 +667,193 @@ if [ -z "$files_to_process" ]; then
   exit 0
 fi
 
-echo "$files_to_process" > so_far.txt
+if [[ "$EPHEMERAL" != true ]]; then
+  echo "$files_to_process" > so_far.txt
+  {
+    echo "$loop_count"
+    echo "$files_to_process"
+  } >"$LAST_SESSION_FILE"
+fi
 total_tsv_files=$(echo "$files_to_process" | wc -l | xargs)
 current_tsv_index=0
 
-if [ ! -f check.csv ]; then
-  echo "File,English,Finnish" >check.csv
+# Every selected scenario is read once up front, for its size.
+declare -A file_sizes
+while IFS=$'\t' read -r size file; do
+  file_sizes["$file"]="$size"
+done < <(echo "$files_to_process" | scenario_sizes)
+
+declare -A per_file_count
+if [[ -n "$spread_mode" ]]; then
+  while IFS=$'\t' read -r count file; do
+    per_file_count["$file"]="$count"
+  done < <(echo "$files_to_process" | while IFS= read -r file; do
+    printf '%s\t%s\n' "${file_sizes["$file"]}" "$file"
+  done | allocate_session_counts "$loop_count" "$spread_mode")
+  echo "Playing ${loop_count} sentences in total, spread ${spread_mode}ly across ${total_tsv_files} scenarios."
+fi
+
+# Sentences missed in earlier sessions (including ones quit before the end)
+# can be played first. Rows whose scenario can't be found right now (an
+# unmounted shared folder, a renamed file) are skipped but kept in the file.
+recovery_lines=""
+if [[ "$EPHEMERAL" != true && -s "$RECOVERY_FILE" ]]; then
+  available_entries=$(while IFS= read -r entry; do
+    if [[ -f "${entry%%$'\t'*}" ]]; then echo "$entry"; fi
+  done <"$RECOVERY_FILE")
+  missing_files=$(cut -f1 "$RECOVERY_FILE" | sort -u | while IFS= read -r file; do
+    if [[ ! -f "$file" ]]; then echo "$file"; fi
+  done)
+  if [[ -n "$missing_files" ]]; then
+    echo "Note: Some missed sentences come from scenarios that aren't there right now."
+    echo "They stay in ${RECOVERY_FILE} until the files are back (or you fix their paths there):"
+    echo "$missing_files" | sed 's/^/  /'
+  fi
+  if [[ -n "$available_entries" ]]; then
+    read -p "You have $(echo "$available_entries" | wc -l | xargs) missed sentence(s) from earlier sessions. Start with them? [Y/n]: " start_with_missed
+    if [[ -z "$start_with_missed" || "$start_with_missed" == "y"* || "$start_with_missed" == "Y"* ]]; then
+      recovery_lines="$available_entries"
+    fi
+  fi
 fi
 
-# MODIFICATION 2.2: Change the main loop to use process substitution.
-# This prevents the loop from running in a subshell, allowing `exit` to be global.
+# The whole session's size, for the progress header: a scenario smaller than
+# its count is played once through.
+session_total=$(echo -n "$recovery_lines" | grep -c '^')
 while IFS= read -r file; do
-  current_tsv_index=$((current_tsv_index + 1))
+  file_size=${file_sizes["$file"]}
+  file_loop_count=${per_file_count["$file"]:-$loop_count}
+  session_total=$((session_total + (file_size < file_loop_count ? file_size : file_loop_count)))
+done < <(echo "$files_to_process")
+sentences_done=0
+sentences_correct=0
+session_start=$SECONDS
 
-  # MODIFICATION 1.2: Create a temporary copy of the scenario file in RAM (/dev/shm)
-  temp_file="/dev/shm/finyap_practice_$(basename "$file")"
-  cp "$file" "$temp_file"
+if [[ "$EPHEMERAL" != true && ! -f check.csv ]]; then
+  echo "File,English,Finnish" >check.csv
+fi
 
-  # --- MODIFIED SETUP ---
-  # MODIFICATION 1.3: Use the in-memory temp_file for all operations
-  echo "Preparing scenario: $file (processing from RAM)..."
+session_missed=() # "file<TAB>line" rows missed this session, for the recovery passes
+session_extra=() # "file<TAB>line" rows added after a miss, see similar_sentence_rows
+scenario_corpus="" # Read the first time a miss needs it
 
-  # 1. Build the word list from the ENTIRE scenario file for a complete fzf list.
-  all_finnish_words=$(cut -f1 "$temp_file" | tr -s '[:space:]' '\n' |
-    while IFS= read -r word_token; do
-      cleaned=$(clean_word "$word_token")
-      if [[ -n "$cleaned" ]]; then echo "$cleaned"; fi
-    done | sort -u | grep -v '^$')
-
-  # 2. Separately, get the specific lines we will actually play for this session.
-  game_lines=$(shuf -n "$loop_count" "$temp_file")
+# Missed sentences first, each with the word list of the scenario it came from.
+if [[ -n "$recovery_lines" ]]; then
+  session_phase="[missed earlier]"
+  play_queued_sentences "$(echo "$recovery_lines" | wc -l | xargs)" <<<"$recovery_lines"
+  session_phase=""
+fi
 
-  if [[ -z "$all_finnish_words" || -z "$game_lines" ]]; then
-    echo "Warning: Could not extract words or sentences from '$file'. Skipping."
-    # MODIFICATION 1.4: Clean up the temp file before continuing
+# With --order round-robin or mixed, every scenario's sentences are sampled up
+# front into one queue of "file<TAB>line" rows. Round-robin takes the first of
+# each scenario, then the second of each, and so on.
+if [[ "$ORDER" != blocked ]]; then
+  queued_rows=$(while IFS= read -r file; do
+    file_loop_count=${per_file_count["$file"]:-$loop_count}
+    if [[ "$file_loop_count" -eq 0 ]]; then
+      continue
+    fi
+    # Sampled from a file as in the blocked loop below (shuf samples a pipe
+    # differently), so a seed picks the same sentences in every order.
+    temp_file="/dev/shm/finyap_practice_$(basename "$file")"
+    normalize_tsv <"$file" | merge_translations >"$temp_file"
+    SEED="${SEED:+$SEED:$file}" seeded_shuf -n "$file_loop_count" "$temp_file" |
+      while IFS= read -r line; do printf '%s\t%s\n' "$file" "$line"; done
     rm -f "$temp_file"
-    sleep 2
-    continue
+  done <<<"$files_to_process")
+  if [[ "$ORDER" == round-robin ]]; then
+    queued_rows=$(echo "$queued_rows" | awk -F'\t' '{ print ++nth[$1] "\t" NR "\t" $0 }' |
+      sort -t$'\t' -k1,1n -k2,2n | cut -f3-)
+  else
+    queued_rows=$(echo "$queued_rows" | seeded_shuf)
+  fi
+  if [[ -n "$queued_rows" ]]; then
+    session_phase="[${ORDER}]"
+    play_queued_sentences "$(echo "$queued_rows" | wc -l | xargs)" <<<"$queued_rows"
+    session_phase=""
   fi
+else
+  # MODIFICATION 2.2: Change the main loop to use process substitution.
+  # This prevents the loop from running in a subshell, allowing `exit` to be global.
+  while IFS= read -r file; do
+    current_tsv_index=$((current_tsv_index + 1))
+    file_loop_count=${per_file_count["$file"]:-$loop_count}
+    if [[ "$file_loop_count" -eq 0 ]]; then
+      continue
+    fi
 
-  # Loop for the number of rounds, using the pre-sampled lines.
-  round_num=0
-  echo "$game_lines" | while read -r line_for_round; do
-    round_num=$((round_num + 1))
-    # Call the efficient game function with the full word list
-    # We still pass the *original* filename for display purposes.
-    run_game_round "$file" "$round_num" "$loop_count" "$all_finnish_words" "$line_for_round"
-  done
+    # MODIFICATION 1.2: Create a temporary copy of the scenario file in RAM (/dev/shm)
+    # The copy is normalized on the way in (BOM, CRLF, comments, quoted fields),
+    # and repeated Finnish sentences are merged into one row.
+    temp_file="/dev/shm/finyap_practice_$(basename "$file")"
+    normalize_tsv <"$file" | merge_translations >"$temp_file"
 
-  # MODIFICATION 1.5: Remove the temporary file from RAM after processing
-  rm -f "$temp_file"
+    # --- MODIFIED SETUP ---
+    # MODIFICATION 1.3: Use the in-memory temp_file for all operations
+    echo "Preparing scenario: $file (processing from RAM)..."
 
-done < <(echo "$files_to_process")
+    # 1. Build the word list from the ENTIRE scenario file for a complete fzf list.
+    all_finnish_words=$(scenario_word_list <"$temp_file")
+
+    # 2. Separately, get the specific lines we will actually play for this session.
+    # Each file gets its own seed, or every file would draw from the same positions.
+    game_lines=$(SEED="${SEED:+$SEED:$file}" seeded_shuf -n "$file_loop_count" "$temp_file")
+
+    if [[ -z "$all_finnish_words" || -z "$game_lines" ]]; then
+      echo "Warning: Could not extract words or sentences from '$file'. Skipping."
+      # MODIFICATION 1.4: Clean up the temp file before continuing
+      rm -f "$temp_file"
+      sleep 2
+      continue
+    fi
+
+    # Loop for the number of rounds, using the pre-sampled lines.
+    # A here-string rather than a pipe keeps the loop out of a subshell, so
+    # the session counters survive it.
+    round_num=0
+    while read -r line_for_round; do
+      round_num=$((round_num + 1))
+      # Call the efficient game function with the full word list
+      # We still pass the *original* filename for display purposes.
+      run_game_round "$file" "$round_num" "$file_loop_count" "$all_finnish_words" "$line_for_round"
+    done <<<"$game_lines"
+
+    # MODIFICATION 1.5: Remove the temporary file from RAM after processing
+    rm -f "$temp_file"
+
+  done < <(echo "$files_to_process")
+fi
 
 echo "All selected scenarios processed."
+
+# Sentences added after a miss; more can be added while they are played.
+while [[ ${#session_extra[@]} -gt 0 ]]; do
+  extra_lines=$(printf '%s\n' "${session_extra[@]}")
+  session_extra=()
+  session_phase="[added after a miss]"
+  play_queued_sentences "$(echo "$extra_lines" | wc -l | xargs)" <<<"$extra_lines"
+  session_phase=""
+done
+
+# Recovery passes: replay this session's misses until a pass is clean.
+pass=1
+while [[ ${#session_missed[@]} -gt 0 && $pass -le $RECOVERY_PASSES ]]; do
+  pass_lines=$(printf '%s\n' "${session_missed[@]}" | awk '!seen[$0]++')
+  pass_total=$(echo "$pass_lines" | wc -l | xargs)
+  session_missed=()
+  session_total=$((session_total + pass_total))
+  echo "Recovery pass ${pass}/${RECOVERY_PASSES}: replaying ${pass_total} missed sentence(s)..."
+  sleep 2
+  session_phase="[recovery pass ${pass}/${RECOVERY_PASSES}]"
+  play_queued_sentences "$pass_total" <<<"$pass_lines"
+  session_phase=""
+  pass=$((pass + 1))
+done
+if [[ ${#session_missed[@]} -gt 0 && "$EPHEMERAL" == true ]]; then
+  echo "$(printf '%s\n' "${session_missed[@]}" | sort -u | wc -l | xargs) sentence(s) still missed."
+elif [[ ${#session_missed[@]} -gt 0 ]]; then
+  echo "$(printf '%s\n' "${session_missed[@]}" | sort -u | wc -l | xargs) sentence(s) still missed; they stay in ${RECOVERY_FILE} for next time."
+elif [[ $pass -gt 1 ]]; then
+  echo "Recovery pass $((pass - 1)) was clean."
+fi
 exit 0
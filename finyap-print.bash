@@ -0,0 +1,162 @@
+#!/bin/bash
+
+# finyap-print: Paper worksheets from scenario files
+# Renders a TSV scenario as a plain-text worksheet: each English sentence with
+# its Finnish masked out and a line to write on, followed by an answer key.
+
+FINYAP_VERSION="0.0.2"
+BLANKS=cipher # How the Finnish is masked on the worksheet: cipher or blank
+COUNT=""      # Empty means every sentence in the file
+FORMAT=txt    # Output format: txt or pdf, see --format
+
+# --- Help and Version Functions ---
+show_help() {
+  cat <<EOF
+Usage: $(basename "$0") [options] FILE
+
+Print a finyap scenario as a paper worksheet with an answer key.
+
+The worksheet goes to standard output; the answer key follows on a new page
+(separated by a form feed, which most printers and pagers honour).
+
+Options:
+  -h, --help        Show this help message and exit.
+      --version     Show script version and exit.
+  --blanks STYLE    How to mask the Finnish: 'cipher' (the default, the same
+                    vowel cipher as the game) or 'blank' (underscores only).
+  --count N         Only print N randomly chosen sentences.
+  --format FORMAT   'txt' (the default) or 'pdf'. A PDF is made with enscript
+                    and ps2pdf (from Ghostscript), which must be installed;
+                    redirect it to a file, e.g. > worksheet.pdf.
+EOF
+}
+
+# --- Argument Parsing ---
+SENTENCE_FILE=""
+while [[ $# -gt 0 ]]; do
+  key="$1"
+  case $key in
+  -h | --help)
+    show_help
+    exit 0
+    ;;
+  --version)
+    echo "$(basename "$0") version $FINYAP_VERSION"
+    exit 0
+    ;;
+  --blanks)
+    if [[ "$2" != "cipher" && "$2" != "blank" ]]; then
+      echo "Error: --blanks must be 'cipher' or 'blank'." >&2
+      exit 1
+    fi
+    BLANKS="$2"
+    shift 2
+    ;;
+  --count)
+    if ! [[ "$2" =~ ^[0-9]+$ ]]; then
+      echo "Error: --count requires a number." >&2
+      exit 1
+    fi
+    COUNT="$2"
+    shift 2
+    ;;
+  --format)
+    if [[ "$2" != "txt" && "$2" != "pdf" ]]; then
+      echo "Error: --format must be 'txt' or 'pdf'." >&2
+      exit 1
+    fi
+    FORMAT="$2"
+    shift 2
+    ;;
+  -*)
+    echo "Error: Unknown option '$1'." >&2
+    exit 1
+    ;;
+  *)
+    SENTENCE_FILE="$1"
+    shift
+    ;;
+  esac
+done
+
+if [[ -z "$SENTENCE_FILE" ]]; then
+  show_help >&2
+  exit 1
+fi
+if [[ ! -r "$SENTENCE_FILE" ]]; then
+  echo "Error: Sentence file '$SENTENCE_FILE' not found or not readable." >&2
+  exit 1
+fi
+if [[ "$FORMAT" == "pdf" ]]; then
+  for tool in enscript ps2pdf iconv; do
+    if ! command -v "$tool" &>/dev/null; then
+      echo "Error: --format pdf needs enscript, ps2pdf (from Ghostscript) and iconv, but '$tool' is not installed." >&2
+      exit 1
+    fi
+  done
+fi
+
+# --- Helper function to mask a Finnish sentence for paper ---
+# Same vowel classes as cipher_word in finyap.bash; punctuation is kept.
+mask_sentence() {
+  if [[ "$BLANKS" == "blank" ]]; then
+    echo "$1" | sed -e 's/[[:alpha:]]/_/g'
+  else
+    echo "$1" | sed \
+      -e 's/[aouAOU]/U/g' \
+      -e 's/[eiEI]/E/g' \
+      -e 's/[äöyÄÖY]/Ä/g' \
+      -e 's/[bcdfghjklmnpqrstvwxzBCDFGHJKLMNPQRSTVWXZ]/x/g'
+  fi
+}
+
+# --- Scenario file reading (normalize_tsv, merge_translations, to_nfc) ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
+# Repeated Finnish rows become one item, with every translation as its prompt.
+lines=$(normalize_tsv <"$SENTENCE_FILE" | merge_translations)
+if [[ -n "$COUNT" ]]; then
+  lines=$(echo "$lines" | shuf -n "$COUNT")
+fi
+if [[ -z "$lines" ]]; then
+  echo "Error: No sentences found in '$SENTENCE_FILE'." >&2
+  exit 1
+fi
+
+# --- Helper function to print the worksheet and its answer key as text ---
+print_worksheet() {
+  echo "finyap worksheet: $(basename "$SENTENCE_FILE" .tsv)"
+  if [[ "$BLANKS" == "cipher" ]]; then
+    echo "Write the Finnish. U = a/o/u, E = e/i, Ä = ä/ö/y, x = consonant."
+  else
+    echo "Write the Finnish. Each _ is one letter."
+  fi
+  echo ""
+  number=0
+  while IFS=$'\t' read -r finnish english _; do
+    number=$((number + 1))
+    printf '%3d. %s\n' "$number" "$english"
+    printf '     %s\n' "$(mask_sentence "$finnish")"
+    printf '     %s\n\n' "$(printf '%*s' "$((${#finnish} + 4))" '' | tr ' ' '_')"
+  done <<<"$lines"
+
+  # --- Answer key, on its own page ---
+  printf '\f'
+  echo "Answer key"
+  echo ""
+  number=0
+  while IFS=$'\t' read -r finnish _; do
+    number=$((number + 1))
+    printf '%3d. %s\n' "$number" "$finnish"
+  done <<<"$lines"
+}
+
+# enscript only reads 8-bit text, so the worksheet goes through Latin-1, which
+# has ä and ö; anything else it lacks (curly quotes, €) is transliterated.
+# It honours the form feed, so the answer key still starts a new page.
+if [[ "$FORMAT" == "pdf" ]]; then
+  print_worksheet | iconv -f UTF-8 -t ISO-8859-1//TRANSLIT |
+    enscript --quiet --no-header --encoding=latin1 --output=- | ps2pdf - -
+else
+  print_worksheet
+fi
@@ -10,6 +10,20 @@
 SENTENCE_FILE='example-sentences.tsv'
 SAMPLED_LINES_COUNT=100 # Number of lines to sample from the large file
 FINYAP_VERSION="0.0.2"
+EXAM_MODE=false    # No live feedback while typing, see --exam
+MASK_STYLE=""      # How unrevealed words are shown: cipher, first-letter, blank, hidden, none or custom, see --mask
+MASK_COMMAND=""    # Command that masks a word when MASK_STYLE is custom, see --mask-command
+HIDE_ENGLISH=false # Hide the translation until the sentence is done, see --hide-english
+SYLLABLES=false    # Show syllable boundaries in masks and answers, see --syllables
+SHOW_IPA=true      # Print an IPA transcription after the round, see --no-ipa
+STRICT_CASE=false  # Require correct capitalization, see --strict
+STRICT_PUNCT=false # Require the word's punctuation, see --strict
+WORD_BANK=false    # Pick each word from 4 candidates instead of the whole list, see --word-bank
+# Command used to play a sentence's audio file, see --audio-player
+AUDIO_PLAYER="${FINYAP_AUDIO_PLAYER:-mpv --really-quiet --no-video}"
+SEED="" # Fixed seed for the random sampling, see --seed
+# How a selected word is judged: 'exact', or a command, see --checker
+ANSWER_CHECKER="${FINYAP_ANSWER_CHECKER:-exact}"
 
 # --- Help and Version Functions ---
 show_help() {
@@ -26,6 +40,53 @@ Options:
       --version   Show script version and exit.
   --input FILE    Use a specific TSV file for sentences, instead
                   of our default, >100,000 line Tatoeba file.
+      --exam      Exam mode. Words are shown as plain blanks instead of
+                  the vowel cipher (unless --mask says otherwise), and the
+                  preview gives no live feedback on what you have typed.
+  --mask STYLE    How to show the words you haven't guessed yet:
+                    cipher        vowel classes and consonants (xUxE), the default
+                    first-letter  first letter plus length (t·····)
+                    blank         underscores only (_____), the default with --exam
+                    hidden        no hint at all, one … per word
+                    none          no mask at all, for reading and typing practice
+  --mask-command CMD
+                  Mask words with your own command instead: it is run with
+                  the word as its argument and prints the masked word.
+      --syllables Show syllable boundaries (vo·kaa·le·ja) in the masked
+                  words and in the correct word once it is revealed.
+                  Not shown with --mask first-letter, which uses the dots.
+                  While guessing, Alt-C, Alt-B and Alt-H switch to the
+                  cipher, blank and hidden masks for the rest of the round,
+                  and Ctrl-T turns the live feedback off and on again.
+  --strict WHAT   Score answers more strictly. WHAT is one of:
+                    case         the capitalization must match (Helsinki, not helsinki)
+                    punctuation  the word's punctuation must match (talossa.)
+                    all          both of the above
+                  By default answers are lowercased and stripped of
+                  punctuation before comparing. May be given more than once.
+      --no-ipa    Don't print the IPA transcription of the sentence and
+                  the last word after the round.
+      --word-bank Easier mode for beginners: each word is picked from
+                  four candidates, the right one and three look-alikes
+                  from the sampled sentences, with the arrow keys.
+      --hide-english
+                  Hide the English translation until the sentence is
+                  finished. Press Ctrl-E during a guess to reveal it early.
+  --audio-player CMD
+                  Command used to play a sentence's audio file (the
+                  optional fourth TSV column) when you press Ctrl-P.
+                  Defaults to \$FINYAP_AUDIO_PLAYER, or
+                  'mpv --really-quiet --no-video'.
+  --checker CMD   How a selected word is judged. The default, 'exact',
+                  accepts only the word itself. Anything else is run as
+                  a command with the target word, the selected word and
+                  the whole sentence as arguments; exit status 0 accepts
+                  the word, and any output is shown to you as a note.
+                  Defaults to \$FINYAP_ANSWER_CHECKER, or 'exact'.
+                  The live preview still compares with the target word.
+  --seed SEED     Make the sentence sampling reproducible: the same seed
+                  and input file always give the same sentence. Requires
+                  openssl.
 EOF
 }
 
@@ -51,6 +112,96 @@ while [[ $# -gt 0 ]]; do
       exit 1
     fi
     ;;
+  --exam)
+    EXAM_MODE=true
+    shift
+    ;;
+  --mask)
+    case "$2" in
+    cipher | first-letter | blank | hidden | none)
+      MASK_STYLE="$2"
+      shift # past argument
+      shift # past value
+      ;;
+    *)
+      echo "Error: --mask must be one of: cipher, first-letter, blank, hidden, none." >&2
+      exit 1
+      ;;
+    esac
+    ;;
+  --mask-command)
+    if [[ -n "$2" ]]; then
+      MASK_STYLE=custom
+      MASK_COMMAND="$2"
+      shift # past argument
+      shift # past value
+    else
+      echo "Error: --mask-command option requires a command." >&2
+      exit 1
+    fi
+    ;;
+  --hide-english)
+    HIDE_ENGLISH=true
+    shift
+    ;;
+  --word-bank)
+    WORD_BANK=true
+    shift
+    ;;
+  --syllables)
+    SYLLABLES=true
+    shift
+    ;;
+  --no-ipa)
+    SHOW_IPA=false
+    shift
+    ;;
+  --strict)
+    case "$2" in
+    case) STRICT_CASE=true ;;
+    punctuation) STRICT_PUNCT=true ;;
+    all)
+      STRICT_CASE=true
+      STRICT_PUNCT=true
+      ;;
+    *)
+      echo "Error: --strict must be one of: case, punctuation, all." >&2
+      exit 1
+      ;;
+    esac
+    shift # past argument
+    shift # past value
+    ;;
+  --audio-player)
+    if [[ -n "$2" ]]; then
+      AUDIO_PLAYER="$2"
+      shift # past argument
+      shift # past value
+    else
+      echo "Error: --audio-player option requires a command." >&2
+      exit 1
+    fi
+    ;;
+  --checker)
+    if [[ -n "$2" ]]; then
+      ANSWER_CHECKER="$2"
+      shift # past argument
+      shift # past value
+    else
+      echo "Error: --checker option requires a command." >&2
+      exit 1
+    fi
+    ;;
+  --seed)
+    if [[ -n "$2" ]]; then
+      SEED="$2"
+      shift # past argument
+      shift # past value
+    else
+      echo "Error: --seed option requires a value." >&2
+      exit 1
+    fi
+    ;;
   *)
     # Unknown options are ignored.
     shift
@@ -58,6 +209,15 @@ while [[ $# -gt 0 ]]; do
   esac
 done
 
+# Exam mode hides every hint unless a mask style was asked for explicitly.
+if [[ -z "$MASK_STYLE" ]]; then
+  if [[ "$EXAM_MODE" == true ]]; then
+    MASK_STYLE=blank
+  else
+    MASK_STYLE=cipher
+  fi
+fi
+
 # --- ANSI Colors ---
 C_HIGHLIGHT=$'\033[42;30m'         # Black Text on Green Background
 C_BG_HIGHLIGHT_PINK=$'\033[45;30m' # Black Text on Pink Background
@@ -82,7 +242,8 @@ if ! command -v bash &>/dev/null; then
   echo "Error: bash is not found. This script requires bash for its preview pane."
   exit 1
 fi
-if [[ ! -f "$SENTENCE_FILE" ]]; then
+# A pipe is fine too, so generated scenarios can be played directly: --input <(...)
+if [[ ! -f "$SENTENCE_FILE" && ! -p "$SENTENCE_FILE" ]]; then
   echo "Error: Sentence file '$SENTENCE_FILE' not found."
   echo "Please create it or change the SENTENCE_FILE variable in the script."
   exit 1
@@ -91,6 +252,10 @@ if [[ ! -r "$SENTENCE_FILE" ]]; then
   echo "Error: Sentence file '$SENTENCE_FILE' is not readable."
   exit 1
 fi
+if [[ -n "$SEED" ]] && ! command -v openssl &>/dev/null; then
+  echo "Error: --seed requires openssl to generate a reproducible random stream."
+  exit 1
+fi
 
 # --- Helper function to print the Finnish flag ---
 print_finnish_flag() {
@@ -116,12 +281,25 @@ print_finnish_flag() {
 }
 
 # --- Helper function to clean a word for matching ---
+# With --strict, case and punctuation are kept and so must be typed exactly.
 clean_word() {
   local word="$1"
-  # Convert to lowercase
-  word=$(echo "$word" | tr '[:upper:]' '[:lower:]')
+  # Internal apostrophes and hyphens are part of the word (vaa'an, linja-auto);
+  # only their typographic variants are swapped for the ones on the keyboard.
+  # Each one is replaced on its own: a [’ʼ] bracket would match their single
+  # bytes under the C locale and mangle ä and ö along with them.
+  word="${word//’/\'}"
+  word="${word//ʼ/\'}"
+  word="${word//‐/-}"
+  word="${word//‑/-}"
+  # Convert to lowercase (${word,,} also folds Ä and Ö, which tr leaves alone)
+  if [[ "$STRICT_CASE" != true ]]; then
+    word="${word,,}"
+  fi
   # Remove leading/trailing punctuation
-  word=$(echo "$word" | sed -E 's/^[[:punct:].,!?;:]+|[[:punct:].,!?;:]+$//g')
+  if [[ "$STRICT_PUNCT" != true ]]; then
+    word=$(echo "$word" | sed -E 's/^[[:punct:].,!?;:]+|[[:punct:].,!?;:]+$//g')
+  fi
   echo "$word"
 }
 
@@ -142,27 +320,199 @@ cipher_word() {
     -e 's/[bcdfghjklmnpqrstvwxzBCDFGHJKLMNPQRSTVWXZ]/x/g'
 }
 
+# --- Helper function to blank out a word entirely ---
+# Letters become underscores; punctuation is kept so the sentence shape survives.
+blank_word() {
+  echo "$1" | sed -e 's/[[:alpha:]]/_/g'
+}
+
+# --- Helper function to hide a word completely ---
+# Not even the length is shown, so only the sentence and its translation help.
+hidden_word() {
+  echo "…"
+}
+
+# --- Helper function to mark syllable boundaries with a middle dot ---
+# Simplified Finnish syllabification: "vokaaleja" -> "vo·kaa·le·ja".
+# 1. A consonant followed by a vowel starts a new syllable, once the current
+#    syllable has a vowel (so "kissa" -> "kis·sa", but "stressi" keeps "st").
+# 2. Two vowels stay together if they are a long vowel or a diphthong, and
+#    never more than two share a syllable ("maailma" -> "maa·il·ma").
+#    ie, uo and yö only count as diphthongs in the first syllable.
+# Anything that isn't a letter (hyphens, punctuation) ends the syllable.
+syllabify_word() {
+  local word="$1"
+  local lower="${word,,}"
+  local vowels="aeiouyäö"
+  local diphthongs=" ai ei oi ui yi äi öi au eu iu ou ey iy äy öy "
+  local first_only=" ie uo yö "
+  local result=""
+  local nucleus=0        # vowels in the current syllable's nucleus so far
+  local first_syllable=true
+  local i c next after pair
+  for ((i = 0; i < ${#word}; i++)); do
+    c="${lower:i:1}"
+    next="${lower:i+1:1}"
+    after="${lower:i+2:1}"
+    result+="${word:i:1}"
+    if [[ "$c" != [[:alpha:]] ]]; then
+      nucleus=0
+      continue
+    fi
+    if [[ "$vowels" == *"$c"* ]]; then
+      nucleus=$((nucleus + 1))
+    elif [[ $nucleus -gt 0 ]]; then
+      nucleus=-1 # past the nucleus, in the syllable's closing consonants
+    fi
+    [[ -z "$next" || "$next" != [[:alpha:]] ]] && continue
+    if [[ "$vowels" != *"$next"* ]]; then
+      # Consonant next: it opens a new syllable if a vowel follows it.
+      if [[ $nucleus -ne 0 && -n "$after" && "$vowels" == *"$after"* ]]; then
+        result+="·"
+        nucleus=0
+        first_syllable=false
+      fi
+    elif [[ "$vowels" == *"$c"* ]]; then
+      # Vowel then vowel: split unless they form a long vowel or diphthong.
+      pair="$c$next"
+      if [[ $nucleus -ge 2 ]] ||
+        ! [[ "$c" == "$next" || "$diphthongs" == *" $pair "* ||
+          ("$first_syllable" == true && "$first_only" == *" $pair "*) ]]; then
+        result+="·"
+        nucleus=0
+        first_syllable=false
+      fi
+    fi
+  done
+  echo "$result"
+}
+
+# --- Helper function for a broad IPA transcription of Finnish text ---
+# Finnish spelling is close to phonemic, so a few rewrites get most of the way:
+# nk/ng become the velar nasal, doubled letters become length marks, every
+# word gets initial stress, and a hyphen marks a compound's secondary stress. "Hyvää päivää" -> "ˈhyʋæː ˈpæiʋæː".
+ipa_transcription() {
+  echo "${1,,}" | sed -E \
+    -e 's/[^[:alpha:][:space:]-]+//g' \
+    -e 's/ng/ŋŋ/g' \
+    -e 's/nk/ŋk/g' \
+    -e 's/([[:alpha:]])\1/\1ː/g' \
+    -e 's/a/ɑ/g' -e 's/ä/æ/g' -e 's/ö/ø/g' -e 's/v|w/ʋ/g' \
+    -e 's/x/ks/g' -e 's/z/ts/g' -e 's/c|q/k/g' -e 's/š/ʃ/g' -e 's/ž/ʒ/g' \
+    -e 's/(^|[[:space:]])([^[:space:]])/\1ˈ\2/g' \
+    -e 's/-/ˌ/g'
+}
+
+# --- Helper function to show only a word's first letter and its length ---
+# "talossa," becomes "t······,": later letters become dots, punctuation is kept.
+# Each part of a hyphenated compound keeps its first letter: "l····-a···".
+first_letter_word() {
+  local word="$1"
+  local masked=""
+  local seen_letter=false
+  local i char
+  for ((i = 0; i < ${#word}; i++)); do
+    char="${word:i:1}"
+    if [[ "$char" == [[:alpha:]] ]]; then
+      if [[ "$seen_letter" == true ]]; then
+        char="·"
+      fi
+      seen_letter=true
+    elif [[ "$char" == "-" ]]; then
+      seen_letter=false
+    fi
+    masked+="$char"
+  done
+  echo "$masked"
+}
+
+# --- Helper function to mask a word according to MASK_STYLE ---
+# The cipher and none styles keep the « and » clitic markers; the first-letter,
+# blank and hidden styles drop them along with the other hints. A custom command gets
+# the plain word and may add markers itself.
+mask_word() {
+  local word_to_mask="$1"
+  if [[ "$SYLLABLES" == true && "$MASK_STYLE" != "first-letter" ]]; then
+    word_to_mask=$(syllabify_word "$word_to_mask")
+  fi
+  if [[ "$MASK_STYLE" == "blank" ]]; then
+    blank_word "$word_to_mask"
+  elif [[ "$MASK_STYLE" == "hidden" ]]; then
+    hidden_word "$word_to_mask"
+  elif [[ "$MASK_STYLE" == "first-letter" ]]; then
+    first_letter_word "$word_to_mask"
+  elif [[ "$MASK_STYLE" == "none" ]]; then
+    add_clitic_markers "$word_to_mask"
+  elif [[ "$MASK_STYLE" == "custom" ]]; then
+    $MASK_COMMAND "$word_to_mask"
+  else
+    cipher_word "$(add_clitic_markers "$word_to_mask")"
+  fi
+}
+
+# --- Helper function to pick word-bank distractors, see --word-bank ---
+# Ranks the words read on stdin by how much they look like the target word $1:
+# letters shared at the start and at the end (same stem, same ending) count
+# for, a difference in length counts against. Prints $2 of the 10 closest,
+# picked at random so the same target doesn't always get the same company.
+similar_words() {
+  awk -v target="$1" '
+    $0 != target {
+      n = length($0) < length(target) ? length($0) : length(target)
+      prefix = 0
+      while (prefix < n && substr($0, prefix + 1, 1) == substr(target, prefix + 1, 1)) prefix++
+      suffix = 0
+      while (suffix < n && substr($0, length($0) - suffix, 1) == substr(target, length(target) - suffix, 1)) suffix++
+      diff = length($0) - length(target)
+      print prefix + suffix - (diff < 0 ? -diff : diff) "\t" $0
+    }
+  ' | sort -t$'\t' -k1,1nr -k2,2 | head -n 10 | cut -f2 | seeded_shuf -n "$2"
+}
+
+# --- Helpers shared with the other scripts, see finyap-lib.bash ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
+# --- Helper function to judge a selected word, see --checker ---
+# Takes the target word, the selected word and the sentence. Returns 0 if the
+# selected word counts as correct; anything printed is a note for the player.
+check_answer() {
+  local target="$1"
+  local selected="$2"
+  local sentence="$3"
+  if [[ "$ANSWER_CHECKER" == "exact" ]]; then
+    [[ "$selected" == "$target" ]]
+  else
+    $ANSWER_CHECKER "$target" "$selected" "$sentence"
+  fi
+}
+
 # --- Helper function to mark clitics for later coloring ---
 # This function wraps clitics with « and » characters. It can handle stacked clitics.
 add_clitic_markers() {
   local word_to_process="$1"
   local temp_word="$word_to_process"
   local processed_clitics_part=""
-  # List of clitics to check for, case-insensitively.
-  local clitics_list=("kaan" "kään" "kin" "han" "hän" "ko" "kö" "pa" "pä")
+  # List of clitics to check for, case-insensitively, each with its place in a stack:
+  # -ko and -kin/-kaan come first, then -pa, then -han (onko«han», sinä«kin»«hän», tule«pa»«han»).
+  local clitics_list=("kaan:1" "kään:1" "kin:1" "ko:1" "kö:1" "pa:2" "pä:2" "han:3" "hän:3")
+  # Clitics are peeled off from the end, so each one found must sit earlier in the stack.
+  local outer_rank=4
 
   while true; do
     local found_in_pass=false
-    for clitic in "${clitics_list[@]}"; do
+    for entry in "${clitics_list[@]}"; do
+      local clitic="${entry%%:*}"
+      local rank="${entry#*:}"
       # Check if the end of the current word part matches a clitic (case-insensitively)
-      if [[ "$(echo "$temp_word" | tr '[:upper:]' '[:lower:]')" == *"$clitic" ]]; then
+      if [[ "$rank" -lt "$outer_rank" && "${temp_word,,}" == *"$clitic" ]]; then
         local suffix_len=${#clitic}
         local original_clitic_case="${temp_word: -$suffix_len}"
-        if [[ "$(echo "$original_clitic_case" | tr '[:upper:]' '[:lower:]')" == "$clitic" ]]; then
+        if is_clitic_host "${temp_word::-$suffix_len}" "$clitic"; then
           # It's a match. Prepend the marked clitic to our result string.
           processed_clitics_part="«${original_clitic_case}»${processed_clitics_part}"
           # And remove it from the word we're checking.
           temp_word="${temp_word::-$suffix_len}"
+          outer_rank="$rank"
           found_in_pass=true
           break # Restart inner loop on the now-shortened word
         fi
@@ -182,16 +532,40 @@ run_fzf_preview() {
   local current_fzf_query="$1"
   local current_fzf_selection="$2"
   local query_for_comparison
-  query_for_comparison=$(echo "$current_fzf_query" | tr '[:upper:]' '[:lower:]' | sed -E 's/^[[:punct:].,!?;:]+|[[:punct:].,!?;:]+$//g')
+  query_for_comparison=$(clean_word "$current_fzf_query")
   local selection_for_comparison="$current_fzf_selection"
 
   # Use `echo -e` to correctly render the ANSI color codes in the masked sentence.
   echo -e "${C_BLUE}finyap v${FINYAP_VERSION} - $(date +%Y-%m-%d)${C_RESET}"
   echo ""
   echo -e "Sentence file: ${C_YELLOW}${SENTENCE_FILE}${C_RESET}"
-  echo -e "Sentence:      $FZF_PREVIEW_MASKED_SENTENCE"
-  echo "English:       $FZF_PREVIEW_ENGLISH_TRANSLATION"
+  # After Alt-C/B/H the mask file names the style, and the sentence comes from
+  # the matching FZF_PREVIEW_MASKED_SENTENCE_<STYLE> variable.
+  local mask_style="$MASK_STYLE"
+  local masked_sentence="$FZF_PREVIEW_MASKED_SENTENCE"
+  if [[ -s "$FZF_PREVIEW_MASK_FILE" ]]; then
+    mask_style=$(<"$FZF_PREVIEW_MASK_FILE")
+    local masked_sentence_variable="FZF_PREVIEW_MASKED_SENTENCE_${mask_style^^}"
+    masked_sentence="${!masked_sentence_variable}"
+  fi
+  echo -e "Sentence:      $masked_sentence"
+  if [[ "$HIDE_ENGLISH" == true && ! -e "$FZF_PREVIEW_REVEAL_FILE" ]]; then
+    echo -e "English:       ${C_GREY}(hidden, press Ctrl-E to reveal)${C_RESET}"
+  else
+    echo "English:       $FZF_PREVIEW_ENGLISH_TRANSLATION"
+  fi
+  if [[ -n "$FZF_PREVIEW_AUDIO_FILE" ]]; then
+    echo -e "Audio:         ${C_GREY}press Ctrl-P to play${C_RESET}"
+  fi
   echo ""
+  # In exam mode, or with live feedback turned off by Ctrl-T, show the query
+  # as typed and nothing that hints at correctness.
+  if [[ "$EXAM_MODE" == true || -e "$FZF_PREVIEW_QUIET_FILE" ]]; then
+    echo "Typed so far:  ${query_for_comparison}"
+    echo ""
+    echo -e "${C_GREY}Found a bug? Report it at https://github.com/hiAndrewQuinn/finyap/issues/new?labels=bug${C_RESET}"
+    return
+  fi
   if [[ "$FZF_PREVIEW_TARGET_WORD" == "$query_for_comparison" ]]; then
     echo "Typed so far:  ${C_GREEN}${query_for_comparison}${C_RESET}"
   elif [[ "$FZF_PREVIEW_TARGET_WORD" == "$query_for_comparison"* ]]; then
@@ -199,6 +573,9 @@ run_fzf_preview() {
   else
     echo "Typed so far:  ${C_RED}${query_for_comparison}${C_RESET}"
   fi
+  if [[ "$mask_style" == "cipher" || "$mask_style" == "none" ]]; then
+    vowel_harmony_note "$FZF_PREVIEW_TARGET_WORD" "$query_for_comparison"
+  fi
 
   echo ""
   echo -e "${C_GREY}Found a bug? Report it at https://github.com/hiAndrewQuinn/finyap/issues/new?labels=bug${C_RESET}"
@@ -235,17 +612,28 @@ run_fzf_preview() {
 
 }
 # Export the functions and variables for the fzf subshell
-export -f run_fzf_preview print_finnish_flag
-export C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK C_BG_HIGHLIGHT_YELLOW SENTENCE_FILE FINYAP_VERSION C_BLUE C_GREY
+export -f run_fzf_preview print_finnish_flag vowel_harmony_note clean_word
+export STRICT_CASE STRICT_PUNCT MASK_STYLE
+export C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK C_BG_HIGHLIGHT_YELLOW SENTENCE_FILE FINYAP_VERSION C_BLUE C_GREY EXAM_MODE HIDE_ENGLISH
 
 # --- 0. Pre-sample sentences from the large file ---
 echo ""
 echo "Sampling at most $SAMPLED_LINES_COUNT random lines from $SENTENCE_FILE..."
-sampled_data=$(shuf "$SENTENCE_FILE" | head -n "$SAMPLED_LINES_COUNT")
+# Only the sampled sentences are composed (to_nfc) and merged, which is where the
+# time goes on a file the size of tatoeba.tsv. Each sampled Finnish sentence
+# still brings along all of its rows, so no translation is lost from the merge.
+# The unwrapped rows go to a temporary file because they are read twice, and
+# --input may be a pipe.
+unwrapped_file=$(mktemp "${TMPDIR:-/tmp}/finyap_sentences.XXXXXX")
+unwrap_tsv <"$SENTENCE_FILE" >"$unwrapped_file"
+sampled_data=$(seeded_shuf "$unwrapped_file" | awk -F'\t' '!seen[$1]++' | head -n "$SAMPLED_LINES_COUNT" | cut -f1 |
+  awk -F'\t' 'NR == FNR { wanted[$1]; next } $1 in wanted' - "$unwrapped_file" |
+  to_nfc | merge_translations)
+rm -f "$unwrapped_file"
 
 if [[ -z "$sampled_data" ]]; then
   echo "Error: Failed to sample any lines from '$SENTENCE_FILE'."
-  unset -f run_fzf_preview print_finnish_flag # Clean up exported functions on error
+  unset -f run_fzf_preview print_finnish_flag vowel_harmony_note # Clean up exported functions on error
   exit 1
 fi
 echo "Sampling complete. Preparing game..."
@@ -256,7 +644,7 @@ all_finnish_words=$(echo "$sampled_data" | cut -f1 |
   tr -s '[:space:]' '\n' |
   while IFS= read -r word_token; do
     cleaned=$(clean_word "$word_token")
-    if [[ -n "$cleaned" ]]; then
+    if [[ "$cleaned" =~ [[:alnum:]] ]]; then
       echo "$cleaned"
     fi
   done |
@@ -265,43 +653,120 @@ all_finnish_words=$(echo "$sampled_data" | cut -f1 |
 
 if [[ -z "$all_finnish_words" ]]; then
   echo "Error: Could not extract any unique Finnish words from the sampled data."
-  unset -f run_fzf_preview print_finnish_flag
+  unset -f run_fzf_preview print_finnish_flag vowel_harmony_note
   exit 1
 fi
 
 # --- 2. Game Setup: Select a random sentence (FROM SAMPLED DATA) ---
-random_line=$(echo "$sampled_data" | shuf -n 1)
+random_line=$(echo "$sampled_data" | seeded_shuf -n 1)
 
 if [[ -z "$random_line" ]]; then
   echo "Error: Failed to select a random line from the sampled data."
-  unset -f run_fzf_preview print_finnish_flag
+  unset -f run_fzf_preview print_finnish_flag vowel_harmony_note
   exit 1
 fi
 
 finnish_sentence=$(echo "$random_line" | cut -f1)
 english_translation=$(echo "$random_line" | cut -f2)
+# Optional third column: word-by-word glosses or notes, shown after the round.
+sentence_glosses=$(echo "$random_line" | cut -f3)
+# Optional fourth column: an audio recording of the sentence, relative to the TSV.
+sentence_audio=$(echo "$random_line" | cut -f4)
+if [[ -n "$sentence_audio" && "$sentence_audio" != /* ]]; then
+  sentence_audio="$(dirname "$SENTENCE_FILE")/$sentence_audio"
+fi
+if [[ -n "$sentence_audio" && ! -r "$sentence_audio" ]]; then
+  echo "Warning: Audio file '$sentence_audio' not found. Playing without audio."
+  sentence_audio=""
+fi
 
 IFS=' ' read -r -a words_in_sentence <<<"$finnish_sentence"
 words_in_sentence=(${words_in_sentence[@]}) # Re-evaluate to handle potential extra spaces
 
 if [[ ${#words_in_sentence[@]} -eq 0 ]]; then
   echo "Error: Chosen Finnish sentence from sample is empty or could not be parsed."
-  unset -f run_fzf_preview print_finnish_flag
+  unset -f run_fzf_preview print_finnish_flag vowel_harmony_note
   exit 1
 fi
 
 revealed_words=()
 game_failed=false
+initial_mask_style="$MASK_STYLE"
+
+# --- Helper function to mask the words from index $1 to the end of the sentence ---
+# The word at $1 is the one being guessed and gets the highlight; the clitic
+# markers become pink in both.
+masked_words_from() {
+  local k masked colored
+  local masked_words=()
+  for ((k = $1; k < ${#words_in_sentence[@]}; k++)); do
+    masked=$(mask_word "${words_in_sentence[k]}")
+    if ((k == $1)); then
+      # For the active word, replace markers with special pink-on-green color
+      # and reset back to the standard green highlight.
+      colored=$(echo "$masked" | sed -e "s/«/${C_BG_HIGHLIGHT_PINK}/g" -e "s/»/${C_HIGHLIGHT}/g")
+      masked_words+=("${C_HIGHLIGHT}${colored}${C_RESET}")
+    else
+      # For future words, use standard pink and reset to default text color.
+      colored=$(echo "$masked" | sed -e "s/«/${C_PINK}/g" -e "s/»/${C_RESET}/g")
+      masked_words+=("$colored")
+    fi
+  done
+  echo "${masked_words[*]}"
+}
+
+# --- Helper function to wrap an fzf action argument in safe delimiters ---
+# fzf accepts action(...), action[...], action{...} and more; pick a pair of
+# delimiters that does not occur in the text, e.g. a word ending in ")".
+fzf_action_argument() {
+  local pair
+  for pair in "()" "[]" "{}" "<>" "~~" "!!" "@@" "##" "%%" "^^" "&&" "**" ";;" "//" "||"; do
+    if [[ "$1" != *"${pair:0:1}"* && "$1" != *"${pair:1:1}"* ]]; then
+      echo "${pair:0:1}$1${pair:1:1}"
+      return
+    fi
+  done
+  echo "()"
+}
 
 # Export constant preview variables once
 export FZF_PREVIEW_ENGLISH_TRANSLATION="$english_translation"
 
+# With --hide-english, Ctrl-E creates this file and the preview starts showing
+# the translation. Its existence at the end tells us whether the user peeked.
+export FZF_PREVIEW_REVEAL_FILE
+FZF_PREVIEW_REVEAL_FILE="$(mktemp -u "${TMPDIR:-/tmp}/finyap_reveal.XXXXXX")"
+fzf_extra_args=()
+if [[ "$HIDE_ENGLISH" == true ]]; then
+  fzf_extra_args+=(--bind "ctrl-e:execute-silent(touch '$FZF_PREVIEW_REVEAL_FILE')+refresh-preview")
+fi
+
+# Ctrl-T creates or removes this file; while it exists the preview stops
+# colouring what has been typed, as in exam mode.
+export FZF_PREVIEW_QUIET_FILE
+FZF_PREVIEW_QUIET_FILE="$(mktemp -u "${TMPDIR:-/tmp}/finyap_quiet.XXXXXX")"
+# Alt-C, Alt-B and Alt-H write the chosen mask style into this file, which the
+# preview reads at once and the next word's mask is taken from.
+export FZF_PREVIEW_MASK_FILE
+FZF_PREVIEW_MASK_FILE="$(mktemp -u "${TMPDIR:-/tmp}/finyap_mask.XXXXXX")"
+# Every switch is also logged here, so the summary can say what was used.
+switch_log_file="$(mktemp "${TMPDIR:-/tmp}/finyap_switches.XXXXXX")"
+if [[ "$EXAM_MODE" != true ]]; then
+  fzf_extra_args+=(--bind "ctrl-t:execute-silent(if [ -e '$FZF_PREVIEW_QUIET_FILE' ]; then rm -f '$FZF_PREVIEW_QUIET_FILE'; echo feedback-on; else touch '$FZF_PREVIEW_QUIET_FILE'; echo feedback-off; fi >>'$switch_log_file')+refresh-preview")
+fi
+
+# Ctrl-P plays the sentence's audio in the background so typing can continue.
+export FZF_PREVIEW_AUDIO_FILE="$sentence_audio"
+if [[ -n "$sentence_audio" ]]; then
+  fzf_extra_args+=(--bind "ctrl-p:execute-silent($AUDIO_PLAYER $(printf '%q' "$sentence_audio") </dev/null >/dev/null 2>&1 &)")
+fi
+
 for i in "${!words_in_sentence[@]}"; do
   target_word_original="${words_in_sentence[$i]}"
   target_word_for_matching=$(clean_word "$target_word_original")
 
   # If a "word" is just punctuation or empty after cleaning, skip the guess.
-  if [[ -z "$target_word_for_matching" ]]; then
+  if ! [[ "$target_word_for_matching" =~ [[:alnum:]] ]]; then
     revealed_words+=("$target_word_original")
     continue
   fi
@@ -319,22 +784,11 @@ for i in "${!words_in_sentence[@]}"; do
     display_sentence_array+=("${processed_revealed[@]}")
   fi
 
-  # Part 2: The current word to guess (highlighted, ciphered, with pink clitics)
-  marked_current=$(add_clitic_markers "$target_word_original")
-  ciphered_current=$(cipher_word "$marked_current")
-  # For the active word, replace markers with special pink-on-green color
-  # and reset back to the standard green highlight.
-  colored_current=$(echo "$ciphered_current" | sed -e "s/«/${C_BG_HIGHLIGHT_PINK}/g" -e "s/»/${C_HIGHLIGHT}/g")
-  display_sentence_array+=("${C_HIGHLIGHT}${colored_current}${C_RESET}")
-
-  # Part 3: Future words (ciphered, with pink clitics)
-  for ((j = i + 1; j < ${#words_in_sentence[@]}; j++)); do
-    marked_future=$(add_clitic_markers "${words_in_sentence[j]}")
-    ciphered_future=$(cipher_word "$marked_future")
-    # For future words, use standard pink and reset to default text color.
-    colored_future=$(echo "$ciphered_future" | sed -e "s/«/${C_PINK}/g" -e "s/»/${C_RESET}/g")
-    display_sentence_array+=("$colored_future")
-  done
+  revealed_part="${display_sentence_array[*]}"
+
+  # Parts 2 and 3: the current word and the words after it, masked
+  ciphered_current=$(mask_word "$target_word_original")
+  display_sentence_array+=("$(masked_words_from "$i")")
 
   # Join the array into a string for display
   masked_sentence_for_display="${display_sentence_array[*]}"
@@ -343,17 +797,48 @@ for i in "${!words_in_sentence[@]}"; do
   export FZF_PREVIEW_TARGET_WORD="$target_word_for_matching"
   export FZF_PREVIEW_MASKED_SENTENCE="$masked_sentence_for_display"
 
+  # The same sentence in each mask Alt-C/B/H can switch to, and bindings that
+  # also put the current word's mask in that style into the prompt.
+  fzf_mask_args=()
+  for switch in c:cipher b:blank h:hidden; do
+    switch_style="${switch#*:}"
+    export "FZF_PREVIEW_MASKED_SENTENCE_${switch_style^^}=${revealed_part:+$revealed_part }$(MASK_STYLE="$switch_style" masked_words_from "$i")"
+    switch_prompt=$(MASK_STYLE="$switch_style" mask_word "$target_word_original")
+    fzf_mask_args+=(--bind "alt-${switch%%:*}:execute-silent(echo $switch_style >'$FZF_PREVIEW_MASK_FILE'; echo mask-$switch_style >>'$switch_log_file')+refresh-preview+change-prompt$(fzf_action_argument "  ${switch_prompt} ")")
+  done
+
+  # With --word-bank, offer the target word and three look-alikes, in random order.
+  fzf_candidates="$all_finnish_words"
+  if [[ "$WORD_BANK" == true ]]; then
+    fzf_candidates=$({
+      echo "$target_word_for_matching"
+      echo "$all_finnish_words" | similar_words "$target_word_for_matching" 3
+    } | seeded_shuf)
+  fi
+
   # --- Run fzf ---
   start_time=$(date +%s.%N)
-  selected_word_from_fzf=$(echo "$all_finnish_words" |
+  selected_word_from_fzf=$(echo "$fzf_candidates" |
     fzf --ignore-case --layout=reverse --border \
       --prompt="  ${ciphered_current} " \
       --preview="bash -c 'run_fzf_preview \"\$1\" \"\$2\"' -- {q} {}" \
       --header="${C_BLUE}https://github.com/hiAndrewQuinn/finyap - https://finbug.xyz/ - https://andrew-quinn.me/${C_RESET}" \
       --header-first \
-      --preview-window="up,80%,wrap,border-sharp")
+      --preview-window="up,80%,wrap,border-sharp" \
+      "${fzf_extra_args[@]}" "${fzf_mask_args[@]}")
   end_time=$(date +%s.%N)
 
+  # A mask picked with Alt-C/B/H stays for the rest of the round. The line
+  # echoed below shows the sentence as it was when the word was answered.
+  if [[ -s "$FZF_PREVIEW_MASK_FILE" ]]; then
+    switched_style=$(<"$FZF_PREVIEW_MASK_FILE")
+    if [[ "$switched_style" != "$MASK_STYLE" ]]; then
+      MASK_STYLE="$switched_style"
+      masked_sentence_for_display_variable="FZF_PREVIEW_MASKED_SENTENCE_${MASK_STYLE^^}"
+      masked_sentence_for_display="${!masked_sentence_for_display_variable}"
+    fi
+  fi
+
   duration=$(awk -v s="$start_time" -v e="$end_time" 'BEGIN {print e-s}')
   duration_int=$(printf "%.0f" "$duration") # Integer part for comparison
 
@@ -368,8 +853,13 @@ for i in "${!words_in_sentence[@]}"; do
   formatted_time=$(printf "(%6.1fs)" "$duration")
   guess_time="${time_color}${formatted_time}${C_RESET}"
 
+  target_word_display="$target_word_original"
+  if [[ "$SYLLABLES" == true ]]; then
+    target_word_display=$(syllabify_word "$target_word_original")
+  fi
+
   # ... redone echo here. So that it looks like: [10.3] Hän pirtää xUxUU.
-  echo -e "${guess_time} $masked_sentence_for_display    <-    ${time_color}${target_word_original}${C_RESET}"
+  echo -e "${guess_time} $masked_sentence_for_display    <-    ${time_color}${target_word_display}${C_RESET}"
 
   if [[ -z "$selected_word_from_fzf" ]]; then
     echo "${C_YELLOW}No word selected. Game aborted.${C_RESET}"
@@ -377,7 +867,13 @@ for i in "${!words_in_sentence[@]}"; do
     break
   fi
 
-  if [[ "$selected_word_from_fzf" == "$target_word_for_matching" ]]; then
+  checker_notes=$(check_answer "$target_word_for_matching" "$selected_word_from_fzf" "$finnish_sentence")
+  checker_verdict=$?
+  if [[ -n "$checker_notes" ]]; then
+    echo -e "${C_GREY}${checker_notes}${C_RESET}"
+  fi
+
+  if [[ $checker_verdict -eq 0 ]]; then
     revealed_words+=("$target_word_original")
     # Color the correctly guessed word: stem is green (from context), clitic is pink.
     # We replace the end marker `»` with a simple RESET. The outer `echo` handles
@@ -390,7 +886,15 @@ for i in "${!words_in_sentence[@]}"; do
     echo -e "${C_RED}Not quite. Game over.${C_RESET}"
     echo
     echo -e "You selected:         ${C_RED}${selected_word_from_fzf}${C_RESET}"
-    echo "The correct word was: ${C_GREEN}${target_word_original}${C_RESET}"
+    echo "The correct word was: ${C_GREEN}${target_word_display}${C_RESET}"
+    gradation_hint=$(explain_gradation_error "$target_word_for_matching" "$selected_word_from_fzf")
+    if [[ -z "$gradation_hint" ]]; then
+      gradation_hint=$(explain_length_error "$target_word_for_matching" "$selected_word_from_fzf")
+    fi
+    if [[ -n "$gradation_hint" ]]; then
+      echo
+      echo -e "$gradation_hint"
+    fi
     game_failed=true
     break
   fi
@@ -405,14 +909,60 @@ else
   echo
 fi
 
+if [[ "$HIDE_ENGLISH" == true ]]; then
+  if [[ -e "$FZF_PREVIEW_REVEAL_FILE" ]]; then
+    echo -e "${C_YELLOW}You peeked at the English translation this round.${C_RESET}"
+  else
+    echo -e "${C_GREEN}You worked from the Finnish alone this round.${C_RESET}"
+  fi
+  echo
+fi
+
+# Say which mask was used, so rounds played with different hints can be told
+# apart, and whether it or the live feedback was switched during the round.
+mask_summary="${initial_mask_style}${MASK_COMMAND:+ ($MASK_COMMAND)}"
+switched_styles=$(sed -n 's/^mask-//p' "$switch_log_file" | awk '!seen[$0]++' | paste -sd, - | sed 's/,/, /g')
+if [[ -n "$switched_styles" ]]; then
+  mask_summary+=", switched to ${switched_styles} during the round"
+fi
+echo -e "${C_GREY}Mask: ${mask_summary}${C_RESET}"
+if grep -q '^feedback-off$' "$switch_log_file"; then
+  echo -e "${C_GREY}Live feedback: turned off with Ctrl-T for part of the round${C_RESET}"
+fi
+echo
+
+# Say how the round was scored, so a strict round isn't mistaken for a lenient one.
+if [[ "$STRICT_CASE" == true || "$STRICT_PUNCT" == true ]]; then
+  strict_parts=()
+  [[ "$STRICT_CASE" == true ]] && strict_parts+=("case")
+  [[ "$STRICT_PUNCT" == true ]] && strict_parts+=("punctuation")
+  echo -e "${C_GREY}Scoring: strict (${strict_parts[*]})${C_RESET}"
+  echo
+fi
+if [[ "$WORD_BANK" == true ]]; then
+  echo -e "${C_GREY}Word bank: each word was picked from 4 candidates${C_RESET}"
+  echo
+fi
+
 echo "The full sentence was:"
 echo "Finnish: $finnish_sentence"
 echo "English: $english_translation"
+if [[ -n "$sentence_glosses" ]]; then
+  echo -e "Glosses: ${C_GREY}${sentence_glosses}${C_RESET}"
+fi
+if [[ "$SHOW_IPA" == true ]]; then
+  echo -e "IPA:     ${C_GREY}/$(ipa_transcription "$finnish_sentence")/${C_RESET}"
+  if [[ "$game_failed" == true && -n "$target_word_for_matching" ]]; then
+    echo -e "         ${C_GREY}(the missed word: /$(ipa_transcription "$target_word_for_matching")/)${C_RESET}"
+  fi
+fi
 
 # Clean up exported variables and function
-unset FZF_PREVIEW_TARGET_WORD FZF_PREVIEW_MASKED_SENTENCE FZF_PREVIEW_ENGLISH_TRANSLATION
+rm -f "$FZF_PREVIEW_REVEAL_FILE" "$FZF_PREVIEW_QUIET_FILE" "$FZF_PREVIEW_MASK_FILE" "$switch_log_file"
+unset FZF_PREVIEW_TARGET_WORD FZF_PREVIEW_MASKED_SENTENCE FZF_PREVIEW_ENGLISH_TRANSLATION FZF_PREVIEW_REVEAL_FILE FZF_PREVIEW_AUDIO_FILE
+unset FZF_PREVIEW_QUIET_FILE FZF_PREVIEW_MASK_FILE FZF_PREVIEW_MASKED_SENTENCE_CIPHER FZF_PREVIEW_MASKED_SENTENCE_BLANK FZF_PREVIEW_MASKED_SENTENCE_HIDDEN
 unset C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK
-unset C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK C_BG_HIGHLIGHT_YELLOW SENTENCE_FILE FINYAP_VERSION C_GREY
-unset -f run_fzf_preview print_finnish_flag
+unset C_GREEN C_YELLOW C_RED C_RESET C_PINK C_HIGHLIGHT C_BG_HIGHLIGHT_PINK C_BG_HIGHLIGHT_YELLOW SENTENCE_FILE FINYAP_VERSION C_GREY EXAM_MODE HIDE_ENGLISH
+unset -f run_fzf_preview print_finnish_flag vowel_harmony_note
 
 exit 0
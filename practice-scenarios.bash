@@ -1,5 +1,24 @@
 #!/bin/bash
 
+# --- Options ---
+# Everything except --seed is passed straight through to finyap.bash.
+# A seed also fixes the scenario order here, and each round gets its own
+# seed derived from it so that rounds don't all replay the same sentence.
+SEED=""
+finyap_args=()
+while [[ $# -gt 0 ]]; do
+  if [[ "$1" == "--seed" && -n "$2" ]]; then
+    SEED="$2"
+    shift 2
+  else
+    finyap_args+=("$1")
+    shift
+  fi
+done
+
+# --- Reproducible shuffles (seeded_shuf) ---
+source "$(dirname "${BASH_SOURCE[0]}")/finyap-lib.bash"
+
 # --- NEW SETUP SECTION ---
 
 echo "============================================================"
@@ -14,6 +33,10 @@ if ! command -v fzf &>/dev/null; then
   echo "Aborting."
   exit 1
 fi
+if [[ -n "$SEED" ]] && ! command -v openssl &>/dev/null; then
+  echo "Error: --seed requires openssl to generate a reproducible random stream."
+  exit 1
+fi
 
 read -p "Enter number of reviews per scenario [10]: " user_loop_count
 loop_count=${user_loop_count:-10}
@@ -25,16 +48,31 @@ fi
 echo ""
 
 # 2. Find all TSV files and ask the user which ones to process
-all_tsv_files=$(find scenarios/ -name "*.tsv" | shuf)
+# Scenario directories are read from FINYAP_SCENARIO_DIRS, colon-separated
+# like PATH (e.g. "scenarios/:$HOME/finnish/shared"), defaulting to scenarios/.
+IFS=':' read -r -a scenario_dirs <<<"${FINYAP_SCENARIO_DIRS:-scenarios/}"
+for dir in "${scenario_dirs[@]}"; do
+  if [[ ! -d "$dir" ]]; then
+    echo "Warning: Scenario directory '$dir' not found. Skipping it."
+  fi
+done
+all_tsv_files=$(find "${scenario_dirs[@]}" -name "*.tsv" 2>/dev/null | sort | seeded_shuf)
 total_available_files=$(echo "$all_tsv_files" | wc -l | xargs) # xargs trims whitespace
 
 # Exit if no .tsv files are found
 if [ "$total_available_files" -eq 0 ]; then
-  echo "Error: No .tsv files found in the 'scenarios/' directory."
+  echo "Error: No .tsv files found in: ${scenario_dirs[*]}"
   echo "Please ensure your scenario files are present before running."
   exit 1
 fi
 
+# The same file name in two directories is fine; the full path tells them apart.
+duplicate_names=$(echo "$all_tsv_files" | xargs -d '\n' -n 1 basename | sort | uniq -d)
+if [[ -n "$duplicate_names" ]]; then
+  echo "Note: These scenarios exist in more than one directory; every copy is listed:"
+  echo "$duplicate_names" | sed 's/^/  /'
+fi
+
 echo "Found ${total_available_files} scenarios."
 read -p "Process all of them? [Y/n]: " process_all
 echo ""
@@ -75,7 +113,7 @@ if [ ! -f check.csv ]; then
 fi
 
 # Iterate over the (potentially shuffled) list of selected files
-echo "$files_to_process" | shuf | while read -r file; do
+echo "$files_to_process" | seeded_shuf | while read -r file; do
   current_tsv_index=$((current_tsv_index + 1))
 
   # Use the user-defined loop count
@@ -91,7 +129,12 @@ echo "$files_to_process" | shuf | while read -r file; do
     echo ""
 
     # Capture the output of finyap.bash into a variable so we can parse it later.
-    script_output=$(bash finyap.bash --input "$file")
+    # Any options given to this script (e.g. --exam) are passed straight through.
+    round_args=("${finyap_args[@]}")
+    if [[ -n "$SEED" ]]; then
+      round_args+=(--seed "${SEED}:${file}:${i}")
+    fi
+    script_output=$(bash finyap.bash --input "$file" "${round_args[@]}")
 
     finnish_sentence=$(echo "$script_output" | grep "Finnish:" | sed 's/Finnish: //')
     english_sentence=$(echo "$script_output" | grep "English:" | sed 's/English: //')